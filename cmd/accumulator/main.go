@@ -0,0 +1,673 @@
+// Command accumulator operates an accumulator kept in a state directory.
+//
+// The directory holds the public parameters (params.json), the encrypted
+// manager key (manager.key), the operation log and the snapshots. Commands
+// that need the manager key read its password from the file given with
+// -password-file or from the ACCUMULATOR_PASSWORD environment variable.
+// update-witness, verify and export of the params, the accumulator and the
+// records only read the public parts of the directory and need no password.
+//
+// Usage:
+//
+//	accumulator setup -dir DIR [-curve a|d|e|f|g] [-security 80|112|128] [-rbits N] [-qbits N] [-d N] [-bitlimit N]
+//	accumulator setup -dir DIR [...] -cache [-cache-dir DIR]
+//	accumulator setup -dir DIR -param-set a-80|a-112|a-128|f-128
+//	accumulator setup -dir DIR -pbc-params FILE
+//	accumulator add -dir DIR -public-key KEY [-attributes A] [-role R]
+//	accumulator delete -dir DIR -public-key KEY
+//	accumulator witness -dir DIR -public-key KEY [-out FILE]
+//	accumulator update-witness (-dir DIR | -params FILE) -witness FILE [-out FILE] [RECORDS...]
+//	accumulator verify (-dir DIR | -params FILE) -witness FILE -accumulator FILE
+//	accumulator show -dir DIR [-members] [-verify]
+//	accumulator export -dir DIR [-since EPOCH] [-out FILE] params|accumulator|records|rotations
+//	accumulator rotate -dir DIR [-witnesses DIR]
+//	accumulator serve -dir DIR [-addr ADDR] [-token-file FILE]
+//
+// setup generates the preset curve of the type and security level; -rbits,
+// -qbits, -d and -bitlimit override its sizes, and Type D and G curves,
+// which have no presets, need them, as does Type F at 112 bits. With -cache
+// generated parameters are kept in the user cache directory, or in
+// -cache-dir, and reused by later setups of the same curve. -param-set uses
+// one of the parameter sets shipped with the package and -pbc-params those
+// of a pbc parameter file.
+//
+// add and delete print the published update record as one line of JSON.
+// Records are exported and read as JSON lines, one record per line.
+//
+// rotate replaces the manager key, prints the signed rotation record and
+// writes a witness under the new key for every member. The new key is
+// stored as manager.key.next until the rotation is complete; a command
+// interrupted in between is finished by the next command that opens the
+// directory.
+//
+// serve answers enrollment, revocation and witness requests only if they
+// carry the bearer token read from -token-file; without it the service only
+// serves the public data.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/Nik-U/pbc"
+
+	"github.com/neucc1997/Accumulator"
+	"github.com/neucc1997/Accumulator/server"
+)
+
+const (
+	paramsFile  = "params.json"
+	keyFile     = "manager.key"
+	nextKeyFile = keyFile + ".next"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"setup", "create a new accumulator and manager key in a directory", runSetup},
+	{"add", "add a member", runAdd},
+	{"delete", "delete a member", runDelete},
+	{"witness", "issue a witness for a member", runWitness},
+	{"update-witness", "bring a witness up to date with update records", runUpdateWitness},
+	{"verify", "verify a witness against an accumulator", runVerify},
+	{"show", "print the state of the accumulator", runShow},
+	{"export", "write the params, the accumulator, the update or the rotation records", runExport},
+	{"rotate", "replace the manager key and re-issue all witnesses", runRotate},
+	{"serve", "run the HTTP manager service", runServe},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "accumulator %s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: accumulator <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-15s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run accumulator <command> -h for the flags of a command.")
+}
+
+// state holds the flags shared by the commands that work on a directory.
+type state struct {
+	dir          string
+	passwordFile string
+}
+
+func (s *state) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.dir, "dir", "", "state directory")
+	fs.StringVar(&s.passwordFile, "password-file", "", "file holding the password of the manager key")
+}
+
+func (s *state) password() ([]byte, error) {
+	if s.passwordFile != "" {
+		data, err := os.ReadFile(s.passwordFile)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.TrimRight(data, "\r\n"), nil
+	}
+	if pw, ok := os.LookupEnv("ACCUMULATOR_PASSWORD"); ok {
+		return []byte(pw), nil
+	}
+	return nil, errors.New("no password: use -password-file or set ACCUMULATOR_PASSWORD")
+}
+
+// open loads the manager key and recovers the manager of the directory.
+func (s *state) open() (*accumulator.Manager, error) {
+	if s.dir == "" {
+		return nil, errors.New("-dir is required")
+	}
+	pw, err := s.password()
+	if err != nil {
+		return nil, err
+	}
+	key, err := accumulator.LoadManagerKey(filepath.Join(s.dir, keyFile), pw)
+	if err != nil {
+		return nil, err
+	}
+	m, err := accumulator.Recover(s.dir, key)
+	if errors.Is(err, accumulator.ErrKeyMismatch) {
+		// The state may be past a rotation that did not install its key.
+		next, nerr := accumulator.LoadManagerKey(filepath.Join(s.dir, nextKeyFile), pw)
+		if nerr != nil {
+			return nil, err
+		}
+		if m, err = accumulator.Recover(s.dir, next); err != nil {
+			return nil, err
+		}
+		if err := s.installKey(next); err != nil {
+			m.Close()
+			return nil, err
+		}
+	}
+	return m, err
+}
+
+// installKey makes the saved next key the key of the directory and writes
+// its public parameters.
+func (s *state) installKey(next *accumulator.ManagerKey) error {
+	if err := os.Rename(filepath.Join(s.dir, nextKeyFile), filepath.Join(s.dir, keyFile)); err != nil {
+		return err
+	}
+	return accumulator.WritePublicParams(filepath.Join(s.dir, paramsFile), next.Params)
+}
+
+// history reads the history of the directory without the manager key,
+// decoding it with pp or, if pp is nil, with the params of the directory.
+func (s *state) history(pp *accumulator.PublicParams) (*accumulator.History, error) {
+	if s.dir == "" {
+		return nil, errors.New("-dir is required")
+	}
+	if pp == nil {
+		var err error
+		if pp, err = s.params(""); err != nil {
+			return nil, err
+		}
+	}
+	return accumulator.LoadHistory(s.dir, pp)
+}
+
+// params loads the public parameters from path, or from the state
+// directory if path is empty.
+func (s *state) params(path string) (*accumulator.PublicParams, error) {
+	if path == "" {
+		if s.dir == "" {
+			return nil, errors.New("-dir or -params is required")
+		}
+		path = filepath.Join(s.dir, paramsFile)
+	}
+	return accumulator.ReadPublicParams(path)
+}
+
+func runSetup(args []string) error {
+	fs := flag.NewFlagSet("setup", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	curveType := fs.String("curve", "a", "curve type: a, d, e, f or g")
+	security := fs.Int("security", 80, "security level in bits: 80, 112 or 128")
+	rbits := fs.Uint("rbits", 0, "bits of the group order (default from -security)")
+	qbits := fs.Uint("qbits", 0, "bits of the base field (default from -security)")
+	d := fs.Uint("d", 0, "discriminant of Type D and G curves")
+	bitlimit := fs.Uint("bitlimit", 0, "bound on the field bits of the Type D and G search")
+	cache := fs.Bool("cache", false, "reuse generated pairing parameters from the cache")
+	cacheDir := fs.String("cache-dir", "", "cache directory (default the user cache directory)")
+	paramSet := fs.String("param-set", "", "shipped parameter set to use instead of generating a curve")
+	pbcParams := fs.String("pbc-params", "", "pbc pairing parameter file to use instead of generating a curve")
+	fs.Parse(args)
+	if s.dir == "" {
+		return errors.New("-dir is required")
+	}
+	pw, err := s.password()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, keyFile)); err == nil {
+		return fmt.Errorf("%s already holds a manager key", s.dir)
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	var key *accumulator.ManagerKey
+	if *paramSet != "" {
+		if key, err = accumulator.SetupManagerKeyNamed(*paramSet); err != nil {
+			return err
+		}
+	} else if *pbcParams != "" {
+		params, err := readPairingParams(*pbcParams)
+		if err != nil {
+			return err
+		}
+		key = accumulator.SetupManagerKeyParams(params)
+	} else {
+		curve, err := accumulator.CurveFor(accumulator.CurveType(*curveType), accumulator.SecurityLevel(*security))
+		if err != nil {
+			if *rbits == 0 {
+				return err
+			}
+			curve = accumulator.Curve{Type: accumulator.CurveType(*curveType)}
+		}
+		for _, f := range []struct {
+			field *uint32
+			flag  uint
+		}{{&curve.RBits, *rbits}, {&curve.QBits, *qbits}, {&curve.D, *d}, {&curve.BitLimit, *bitlimit}} {
+			if f.flag != 0 {
+				*f.field = uint32(f.flag)
+			}
+		}
+		if *cache || *cacheDir != "" {
+			params, err := curve.GenerateCached(*cacheDir)
+			if err != nil {
+				return err
+			}
+			key = accumulator.SetupManagerKeyParams(params)
+		} else if key, err = accumulator.SetupManagerKeyCurve(curve); err != nil {
+			return err
+		}
+	}
+	if err := accumulator.SaveManagerKey(filepath.Join(s.dir, keyFile), key, pw); err != nil {
+		return err
+	}
+	if err := accumulator.WritePublicParams(filepath.Join(s.dir, paramsFile), key.Params); err != nil {
+		return err
+	}
+	m, err := accumulator.Recover(s.dir, key)
+	if err != nil {
+		return err
+	}
+	return m.Close()
+}
+
+func contentFlags(fs *flag.FlagSet) *accumulator.AccumulatorContent {
+	content := new(accumulator.AccumulatorContent)
+	fs.StringVar(&content.PublicKey, "public-key", "", "public key of the member")
+	fs.StringVar(&content.Attributes, "attributes", "", "attributes of the member")
+	fs.StringVar(&content.Role, "role", "", "role of the member")
+	return content
+}
+
+func runAdd(args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	content := contentFlags(fs)
+	fs.Parse(args)
+	if content.PublicKey == "" {
+		return errors.New("-public-key is required")
+	}
+	m, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	rec, err := m.Add(*content)
+	if err != nil {
+		return err
+	}
+	return writeJSON(os.Stdout, rec)
+}
+
+func runDelete(args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	publicKey := fs.String("public-key", "", "public key of the member")
+	fs.Parse(args)
+	if *publicKey == "" {
+		return errors.New("-public-key is required")
+	}
+	m, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	rec, err := m.DeletePublicKey(*publicKey)
+	if err != nil {
+		return err
+	}
+	return writeJSON(os.Stdout, rec)
+}
+
+func runWitness(args []string) error {
+	fs := flag.NewFlagSet("witness", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	publicKey := fs.String("public-key", "", "public key of the member")
+	out := fs.String("out", "", "output file (default standard output)")
+	fs.Parse(args)
+	m, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	member, ok := m.MemberByPublicKey(*publicKey)
+	if !ok {
+		return fmt.Errorf("%q: %w", *publicKey, accumulator.ErrUnknownMember)
+	}
+	wit, err := m.Witness(member.Content)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, wit)
+}
+
+func runUpdateWitness(args []string) error {
+	fs := flag.NewFlagSet("update-witness", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	paramsPath := fs.String("params", "", "public parameters (default the params of -dir)")
+	witnessPath := fs.String("witness", "", "witness to update")
+	out := fs.String("out", "", "output file (default standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: accumulator update-witness [flags] [RECORDS...]")
+		fmt.Fprintln(fs.Output(), "Without record files the records are taken from the history of -dir.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	pp, err := s.params(*paramsPath)
+	if err != nil {
+		return err
+	}
+	wit, err := readWitness(pp, *witnessPath)
+	if err != nil {
+		return err
+	}
+	var records []*accumulator.UpdateRecord
+	if fs.NArg() > 0 {
+		for _, path := range fs.Args() {
+			recs, err := readRecords(pp, path)
+			if err != nil {
+				return err
+			}
+			records = append(records, recs...)
+		}
+	} else {
+		history, err := s.history(pp)
+		if err != nil {
+			return err
+		}
+		if records, err = history.RecordsSince(wit.Epoch()); err != nil {
+			return err
+		}
+	}
+	if err := wit.ApplyUpdates(records); err != nil {
+		return err
+	}
+	return writeOutput(*out, wit)
+}
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	paramsPath := fs.String("params", "", "public parameters (default the params of -dir)")
+	witnessPath := fs.String("witness", "", "witness to verify")
+	accPath := fs.String("accumulator", "", "accumulator to verify against")
+	fs.Parse(args)
+	if *accPath == "" {
+		return errors.New("-accumulator is required")
+	}
+	pp, err := s.params(*paramsPath)
+	if err != nil {
+		return err
+	}
+	wit, err := readWitness(pp, *witnessPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(*accPath)
+	if err != nil {
+		return err
+	}
+	acc, err := pp.DecodeAccumulator(data)
+	if err != nil {
+		return err
+	}
+	if wit.Epoch() != acc.Epoch() {
+		return fmt.Errorf("witness is at epoch %d, accumulator at epoch %d", wit.Epoch(), acc.Epoch())
+	}
+	if !accumulator.VerifyWitness(wit, acc, pp.H, pp.PK2, wit.Element(), pp.Pairing) {
+		return accumulator.ErrInvalidWitness
+	}
+	fmt.Println("valid")
+	return nil
+}
+
+func runShow(args []string) error {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	members := fs.Bool("members", false, "list the members")
+	verify := fs.Bool("verify", false, "recompute the accumulator from the members")
+	fs.Parse(args)
+	m, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	acc := m.Accumulator()
+	fmt.Printf("curve:   type %s\n", m.Params().CurveType())
+	fmt.Printf("epoch:   %d\n", acc.Epoch())
+	fmt.Printf("value:   %s\n", hex.EncodeToString(acc.Value().Bytes()))
+	fmt.Printf("members: %d\n", m.Count())
+	if *verify {
+		if err := m.Verify(); err != nil {
+			return err
+		}
+		fmt.Println("state verified")
+	}
+	if *members {
+		w := bufio.NewWriter(os.Stdout)
+		m.Range(func(member *accumulator.Member) bool {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", hex.EncodeToString(member.Element.Bytes()),
+				member.Content.PublicKey, member.Content.Role, member.Content.Attributes)
+			return true
+		})
+		return w.Flush()
+	}
+	return nil
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	since := fs.Uint64("since", 0, "first epoch to export records after")
+	out := fs.String("out", "", "output file (default standard output)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("export needs one of params, accumulator, records or rotations")
+	}
+	switch fs.Arg(0) {
+	case "params":
+		pp, err := s.params("")
+		if err != nil {
+			return err
+		}
+		return writeOutput(*out, pp)
+	case "accumulator":
+		history, err := s.history(nil)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*out, history.Latest())
+	case "records":
+		history, err := s.history(nil)
+		if err != nil {
+			return err
+		}
+		records, err := history.RecordsSince(*since)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		for _, rec := range records {
+			if err := writeJSON(&buf, rec); err != nil {
+				return err
+			}
+		}
+		return writeBytes(*out, buf.Bytes())
+	case "rotations":
+		m, err := s.open()
+		if err != nil {
+			return err
+		}
+		defer m.Close()
+		var buf bytes.Buffer
+		for _, rot := range m.Rotations() {
+			if err := writeJSON(&buf, rot); err != nil {
+				return err
+			}
+		}
+		return writeBytes(*out, buf.Bytes())
+	default:
+		return fmt.Errorf("cannot export %q", fs.Arg(0))
+	}
+}
+
+func runRotate(args []string) error {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	witnesses := fs.String("witnesses", "", "directory to write the new witnesses to, one file per public key")
+	fs.Parse(args)
+	m, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	pw, err := s.password()
+	if err != nil {
+		return err
+	}
+	next := m.Key().Next()
+	if err := accumulator.SaveManagerKey(filepath.Join(s.dir, nextKeyFile), next, pw); err != nil {
+		return err
+	}
+	rot, err := m.Rotate(next)
+	if err != nil {
+		os.Remove(filepath.Join(s.dir, nextKeyFile))
+		return err
+	}
+	if err := s.installKey(next); err != nil {
+		return err
+	}
+	if *witnesses != "" {
+		if err := os.MkdirAll(*witnesses, 0755); err != nil {
+			return err
+		}
+		m.RangeWitnesses(func(member *accumulator.Member, wit *accumulator.Witness) bool {
+			name := url.PathEscape(member.Content.PublicKey)
+			if name == "" {
+				name = hex.EncodeToString(member.Element.Bytes())
+			}
+			err = writeOutput(filepath.Join(*witnesses, name+".json"), wit)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return writeJSON(os.Stdout, rot)
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var s state
+	s.register(fs)
+	addr := fs.String("addr", "localhost:8080", "listen address")
+	tokenFile := fs.String("token-file", "", "file holding the bearer token of enrollment, revocation and witness requests")
+	fs.Parse(args)
+	var opts []server.Option
+	if *tokenFile != "" {
+		data, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			return err
+		}
+		token := bytes.TrimRight(data, "\r\n")
+		if len(token) == 0 {
+			return fmt.Errorf("%s is empty", *tokenFile)
+		}
+		opts = append(opts, server.WithAuthorizer(server.BearerToken(string(token))))
+	}
+	m, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	if opts == nil {
+		log.Printf("no -token-file: enrollment, revocation and witnesses are refused")
+	}
+	log.Printf("serving %s on %s", s.dir, *addr)
+	return http.ListenAndServe(*addr, server.New(m, opts...))
+}
+
+func readPairingParams(path string) (*pbc.Params, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return pbc.NewParams(f)
+}
+
+func readWitness(pp *accumulator.PublicParams, path string) (*accumulator.Witness, error) {
+	if path == "" {
+		return nil, errors.New("-witness is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return pp.DecodeWitness(data)
+}
+
+// readRecords reads a file of update records, one JSON record per line.
+func readRecords(pp *accumulator.PublicParams, path string) ([]*accumulator.UpdateRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records, err := pp.ReadUpdateRecords(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return records, nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func writeOutput(path string, v interface{}) error {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, v); err != nil {
+		return err
+	}
+	return writeBytes(path, buf.Bytes())
+}
+
+func writeBytes(path string, data []byte) error {
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
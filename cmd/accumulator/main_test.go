@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neucc1997/Accumulator"
+)
+
+// rotatedDir returns a directory with one member whose command saved the
+// next key and, if rotate is set, rotated the manager to it, but stopped
+// before installing it. It also returns the old and the next key.
+func rotatedDir(t *testing.T, rotate bool) (string, *accumulator.ManagerKey, *accumulator.ManagerKey) {
+	t.Helper()
+	t.Setenv("ACCUMULATOR_PASSWORD", "password")
+	dir := t.TempDir()
+	mk, err := accumulator.SetupManagerKeyNamed("a-80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.SaveManagerKey(filepath.Join(dir, keyFile), mk, []byte("password")); err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.WritePublicParams(filepath.Join(dir, paramsFile), mk.Params); err != nil {
+		t.Fatal(err)
+	}
+	m, err := accumulator.Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if _, err := m.Add(accumulator.AccumulatorContent{PublicKey: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	next := mk.Next()
+	if err := accumulator.SaveManagerKey(filepath.Join(dir, nextKeyFile), next, []byte("password")); err != nil {
+		t.Fatal(err)
+	}
+	if rotate {
+		if _, err := m.Rotate(next); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, mk, next
+}
+
+// samePK2 compares the keys of parameters loaded into different pairings.
+func samePK2(a, b *accumulator.PublicParams) bool {
+	return bytes.Equal(a.PK2.Bytes(), b.PK2.Bytes())
+}
+
+func TestOpenInstallsPendingKey(t *testing.T) {
+	dir, _, next := rotatedDir(t, true)
+	s := &state{dir: dir}
+	m, err := s.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if !samePK2(m.Key().Params, next.Params) || len(m.Rotations()) != 1 {
+		t.Fatal("directory not opened with the rotated key")
+	}
+	if _, err := os.Stat(filepath.Join(dir, nextKeyFile)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("next key still pending: %v", err)
+	}
+	key, err := accumulator.LoadManagerKey(filepath.Join(dir, keyFile), []byte("password"))
+	if err != nil || !samePK2(key.Params, next.Params) {
+		t.Errorf("installed key is not the next key: %v", err)
+	}
+	pp, err := accumulator.ReadPublicParams(filepath.Join(dir, paramsFile))
+	if err != nil || !samePK2(pp, next.Params) {
+		t.Errorf("params not those of the next key: %v", err)
+	}
+}
+
+func TestOpenKeepsKeyOfUnstartedRotation(t *testing.T) {
+	dir, mk, _ := rotatedDir(t, false)
+	s := &state{dir: dir}
+	m, err := s.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if !samePK2(m.Key().Params, mk.Params) || len(m.Rotations()) != 0 || m.Count() != 1 {
+		t.Error("directory not opened with its key")
+	}
+}
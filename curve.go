@@ -0,0 +1,152 @@
+package accumulator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Nik-U/pbc"
+)
+
+var ErrUnknownCurve = errors.New("accumulator: unknown curve type or security level")
+
+// CurveType names a family of pbc pairing parameters.
+type CurveType string
+
+const (
+	CurveA CurveType = "a" // supersingular, symmetric, embedding degree 2
+	CurveD CurveType = "d" // MNT, asymmetric, embedding degree 6
+	CurveE CurveType = "e" // complex multiplication, symmetric, embedding degree 1
+	CurveF CurveType = "f" // Barreto-Naehrig, asymmetric, embedding degree 12
+	CurveG CurveType = "g" // Freeman, asymmetric, embedding degree 10
+)
+
+// SecurityLevel is the approximate security of a curve in bits, the lower
+// of the discrete logarithm in G1 and in GT.
+type SecurityLevel int
+
+const (
+	Security80  SecurityLevel = 80
+	Security112 SecurityLevel = 112
+	Security128 SecurityLevel = 128
+)
+
+// Curve describes the pairing parameters to generate. Not every field
+// applies to every type; see the pbc Generate functions.
+type Curve struct {
+	Type     CurveType `json:"type"`
+	RBits    uint32    `json:"rbits"`              // bits of the group order; for Type F also of the field
+	QBits    uint32    `json:"qbits,omitempty"`    // bits of the base field (A, D, E, G)
+	D        uint32    `json:"d,omitempty"`        // discriminant of the CM method (D, G)
+	BitLimit uint32    `json:"bitlimit,omitempty"` // bound on the bits of the field in the CM search (D, G)
+}
+
+// curvePresets holds the sizes that reach each security level. In GT the
+// discrete logarithm is over a field of embedding degree times QBits bits.
+// Type D and G curves are found by a CM search whose discriminant depends on
+// the size, so they have no presets and need all their fields set or a
+// parameter file.
+var curvePresets = map[CurveType]map[SecurityLevel]Curve{
+	CurveA: {
+		Security80:  {Type: CurveA, RBits: 160, QBits: 512},
+		Security112: {Type: CurveA, RBits: 224, QBits: 1024},
+		Security128: {Type: CurveA, RBits: 256, QBits: 1536},
+	},
+	CurveE: {
+		Security80:  {Type: CurveE, RBits: 160, QBits: 1024},
+		Security112: {Type: CurveE, RBits: 224, QBits: 2048},
+		Security128: {Type: CurveE, RBits: 256, QBits: 3072},
+	},
+	// Barreto-Naehrig curves are the fastest here, but the number field
+	// sieve variants found since 2016 leave the 256-bit curve about 100 bits
+	// and take 462 bits for 128 (Barbulescu and Duquesne, "Updating key size
+	// estimations for pairings", 2019). There is no size estimated for 112
+	// bits, so that level has no preset.
+	CurveF: {
+		Security80:  {Type: CurveF, RBits: 256},
+		Security128: {Type: CurveF, RBits: 462},
+	},
+}
+
+// CurveFor returns the preset curve of type t for level.
+func CurveFor(t CurveType, level SecurityLevel) (Curve, error) {
+	c, ok := curvePresets[t][level]
+	if !ok {
+		return Curve{}, fmt.Errorf("%w: type %s at %d bits", ErrUnknownCurve, t, level)
+	}
+	return c, nil
+}
+
+// Generate generates pairing parameters for c. This takes from seconds for
+// small curves to minutes for large Type A and E curves.
+func (c Curve) Generate() (*pbc.Params, error) {
+	switch c.Type {
+	case CurveA:
+		return pbc.GenerateA(c.RBits, c.QBits), nil
+	case CurveD:
+		return pbc.GenerateD(c.D, c.RBits, c.QBits, c.BitLimit)
+	case CurveE:
+		return pbc.GenerateE(c.RBits, c.QBits), nil
+	case CurveF:
+		return pbc.GenerateF(c.RBits), nil
+	case CurveG:
+		return pbc.GenerateG(c.D, c.RBits, c.QBits, c.BitLimit)
+	default:
+		return nil, fmt.Errorf("%w: type %q", ErrUnknownCurve, c.Type)
+	}
+}
+
+// SetupCurve is Setup for any curve.
+func SetupCurve(c Curve) (*PublicParams, *pbc.Element, error) {
+	params, err := c.Generate()
+	if err != nil {
+		return nil, nil, err
+	}
+	pp, key := SetupParams(params)
+	return pp, key, nil
+}
+
+// SetupParams picks random generators and a fresh manager key for existing
+// pairing parameters, e.g. read from a pbc parameter file with
+// pbc.NewParams.
+func SetupParams(params *pbc.Params) (*PublicParams, *pbc.Element) {
+	pairing := params.NewPairing()
+	key := randNonZero(pairing)
+	g := pairing.NewG1().Rand()
+	h := pairing.NewG2().Rand()
+	return &PublicParams{
+		Params:  params.String(),
+		Pairing: pairing,
+		G:       g,
+		H:       h,
+		PK1:     pairing.NewG1().PowZn(g, key),
+		PK2:     pairing.NewG2().PowZn(h, key),
+	}, key
+}
+
+// SetupManagerKeyCurve runs SetupCurve and wraps the generated key.
+func SetupManagerKeyCurve(c Curve) (*ManagerKey, error) {
+	pp, key, err := SetupCurve(c)
+	if err != nil {
+		return nil, err
+	}
+	return &ManagerKey{Params: pp, secret: key}, nil
+}
+
+// SetupManagerKeyParams runs SetupParams and wraps the generated key.
+func SetupManagerKeyParams(params *pbc.Params) *ManagerKey {
+	pp, key := SetupParams(params)
+	return &ManagerKey{Params: pp, secret: key}
+}
+
+// CurveType returns the type named in the pairing parameters.
+func (pp *PublicParams) CurveType() CurveType {
+	scanner := bufio.NewScanner(strings.NewReader(pp.Params))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 && fields[0] == "type" {
+			return CurveType(fields[1])
+		}
+	}
+	return ""
+}
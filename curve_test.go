@@ -0,0 +1,22 @@
+package accumulator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCurveFPresets(t *testing.T) {
+	// Sizes of Barbulescu and Duquesne (2019) for Barreto-Naehrig curves.
+	for level, rbits := range map[SecurityLevel]uint32{Security80: 256, Security128: 462} {
+		c, err := CurveFor(CurveF, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.RBits != rbits {
+			t.Errorf("Type F at %d bits: got %d bits, want %d", level, c.RBits, rbits)
+		}
+	}
+	if _, err := CurveFor(CurveF, Security112); !errors.Is(err, ErrUnknownCurve) {
+		t.Errorf("Type F at 112 bits: got %v, want ErrUnknownCurve", err)
+	}
+}
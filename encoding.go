@@ -0,0 +1,239 @@
+package accumulator
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Nik-U/pbc"
+)
+
+// encodingVersion is the version byte written in front of every binary
+// encoding of this package.
+const encodingVersion = 1
+
+var (
+	ErrInvalidEncoding    = errors.New("accumulator: invalid encoding")
+	ErrUnsupportedVersion = errors.New("accumulator: unsupported encoding version")
+	ErrNotInSubgroup      = errors.New("accumulator: group element is not in the subgroup of prime order")
+)
+
+// encoder appends length-prefixed fields to a buffer.
+type encoder struct {
+	buf []byte
+}
+
+func newEncoder() *encoder {
+	return &encoder{buf: []byte{encodingVersion}}
+}
+
+func (e *encoder) uint64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) bytes(b []byte) {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(b)))
+	e.buf = append(append(e.buf, n[:]...), b...)
+}
+
+// elements writes the number of elements followed by each element.
+func (e *encoder) elements(list []*pbc.Element) {
+	e.uint64(uint64(len(list)))
+	for _, el := range list {
+		e.bytes(el.Bytes())
+	}
+}
+
+// members writes the number of members followed by the length-prefixed
+// element, public key, attributes and role of each.
+func (e *encoder) members(list []*Member) {
+	e.uint64(uint64(len(list)))
+	for _, m := range list {
+		e.bytes(m.Element.Bytes())
+		e.bytes([]byte(m.Content.PublicKey))
+		e.bytes([]byte(m.Content.Attributes))
+		e.bytes([]byte(m.Content.Role))
+	}
+}
+
+// decoder reads the fields written by an encoder. The first error is kept
+// and every later read returns zero values.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func newDecoder(data []byte) *decoder {
+	d := &decoder{buf: data}
+	if len(data) == 0 {
+		d.err = ErrInvalidEncoding
+	} else if data[0] != encodingVersion {
+		d.err = ErrUnsupportedVersion
+	} else {
+		d.buf = data[1:]
+	}
+	return d
+}
+
+func (d *decoder) uint64() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 8 {
+		d.err = ErrInvalidEncoding
+		return 0
+	}
+	v := binary.BigEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return v
+}
+
+func (d *decoder) bytes() []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.buf) < 4 {
+		d.err = ErrInvalidEncoding
+		return nil
+	}
+	n := binary.BigEndian.Uint32(d.buf)
+	if uint64(len(d.buf)-4) < uint64(n) {
+		d.err = ErrInvalidEncoding
+		return nil
+	}
+	b := d.buf[4 : 4+n]
+	d.buf = d.buf[4+n:]
+	return b
+}
+
+// element decodes the next field into el, which must be a fresh element of
+// the expected group.
+func (d *decoder) element(el *pbc.Element) *pbc.Element {
+	if err := setElementBytes(el, d.bytes()); err != nil && d.err == nil {
+		d.err = err
+	}
+	return el
+}
+
+// elements decodes a list written by encoder.elements, creating each element
+// with newElement.
+func (d *decoder) elements(newElement func() *pbc.Element) []*pbc.Element {
+	n := d.uint64()
+	// Every element takes at least its 4-byte length prefix.
+	if d.err != nil || n > uint64(len(d.buf)/4) {
+		if d.err == nil {
+			d.err = ErrInvalidEncoding
+		}
+		return nil
+	}
+	var list []*pbc.Element
+	for i := uint64(0); i < n; i++ {
+		list = append(list, d.element(newElement()))
+	}
+	return list
+}
+
+// members decodes a list written by encoder.members into pairing.
+func (d *decoder) members(pairing *pbc.Pairing) []*Member {
+	n := d.uint64()
+	// Every member takes at least its four 4-byte length prefixes.
+	if d.err == nil && n > uint64(len(d.buf)/16) {
+		d.err = ErrInvalidEncoding
+	}
+	var list []*Member
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		m := &Member{Element: d.element(pairing.NewZr())}
+		m.Content.PublicKey = string(d.bytes())
+		m.Content.Attributes = string(d.bytes())
+		m.Content.Role = string(d.bytes())
+		list = append(list, m)
+	}
+	return list
+}
+
+// finish reports the first decoding error, or an error if data is left over.
+func (d *decoder) finish() error {
+	if d.err == nil && len(d.buf) != 0 {
+		d.err = ErrInvalidEncoding
+	}
+	return d.err
+}
+
+// setElementBytes sets el from b after checking that b has the length of an
+// element of el's group. pbc reads a fixed number of bytes, so it must never
+// see a short buffer. Group elements must also lie in the subgroup of prime
+// order; elements of Zr are told apart by their length.
+func setElementBytes(el *pbc.Element, b []byte) error {
+	if len(b) == 0 || len(b) != el.BytesLen() {
+		return ErrInvalidEncoding
+	}
+	el.SetBytes(b)
+	if len(b) != int(el.Pairing().ZrLength()) && !inSubgroup(el) {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+// SetElementBytes sets el from b with the checks of the decoders of this
+// package, for encodings that carry bare elements: b must have the length
+// of an element of el's group, and a group element must lie in the
+// subgroup of prime order and not be the identity.
+func SetElementBytes(el *pbc.Element, b []byte) error {
+	if err := setElementBytes(el, b); err != nil {
+		return err
+	}
+	if len(b) != int(el.Pairing().ZrLength()) && el.Is0() {
+		return ErrInvalidEncoding
+	}
+	return nil
+}
+
+// inSubgroup reports whether el^r is the identity, r the order of Zr. pbc
+// only checks that a decoded point lies on the curve, and the curves have
+// points outside the subgroup of order r, such as (0, 0) of order 2 on
+// Type A curves, that pair to the identity with everything.
+func inSubgroup(el *pbc.Element) bool {
+	pairing := el.Pairing()
+	order := pairing.NewZr().Set1().ThenNeg().BigInt()
+	order.Add(order, big.NewInt(1))
+	return el.NewFieldElement().PowBig(el, order).Is1()
+}
+
+// decodeHexElement is the JSON counterpart of setElementBytes.
+func decodeHexElement(el *pbc.Element, s string) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	return setElementBytes(el, b)
+}
+
+func encodeHexElements(list []*pbc.Element) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	encoded := make([]string, len(list))
+	for i, el := range list {
+		encoded[i] = hex.EncodeToString(el.Bytes())
+	}
+	return encoded
+}
+
+func decodeHexElements(encoded []string, newElement func() *pbc.Element) ([]*pbc.Element, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	list := make([]*pbc.Element, len(encoded))
+	for i, s := range encoded {
+		list[i] = newElement()
+		if err := decodeHexElement(list[i], s); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
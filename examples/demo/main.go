@@ -0,0 +1,942 @@
+// Command demo walks through the accumulator operations: pairing checks,
+// membership proofs, and adding and deleting members.
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/Nik-U/pbc"
+	"github.com/athanorlabs/go-dleq/types"
+	ring "github.com/neucc1997/ring-go"
+
+	"github.com/neucc1997/Accumulator"
+)
+
+func main() {
+	Test1()
+	Test2()
+	Test3()
+	Test4()
+	Demo()
+	fmt.Println("=================================================")
+	HelperTest()
+	fmt.Println("=================================================")
+	NonMembershipTest()
+	fmt.Println("=================================================")
+	ZeroKnowledgeTest()
+	fmt.Println("=================================================")
+	MemberSignatureTest()
+	fmt.Println("=================================================")
+	LinkableTest()
+	fmt.Println("=================================================")
+	ManagerTest()
+	fmt.Println("=================================================")
+	SubscriptionTest()
+	fmt.Println("=================================================")
+	RotationTest()
+	fmt.Println("=================================================")
+	AsymmetricTest()
+}
+
+// pairing test
+func Test1() {
+	// In a real application, generate this once and publish it
+	params := pbc.GenerateA(160, 512)
+
+	pairing := params.NewPairing()
+
+	// Initialize group elements. pbc automatically handles garbage collection.
+	g := pairing.NewG1()
+	h := pairing.NewG2()
+	x := pairing.NewGT()
+
+	// Generate random group elements and pair them
+	g.Rand()
+	h.Rand()
+	fmt.Printf("g = %s\n", g)
+	fmt.Printf("h = %s\n", h)
+	x.Pair(g, h)
+	fmt.Printf("e(g,h) = %s\n", x)
+
+	fmt.Printf("================\n")
+
+	xt := pairing.NewG1()
+	privKey := pairing.NewZr().Rand()
+	xt.PowZn(g, privKey)
+
+	// gt := pairing.NewG1().Rand()
+
+	temp1 := pairing.NewGT().Pair(h, xt)
+	temp2 := pairing.NewGT().Pair(h, g)
+	temp2.PowZn(temp2, privKey)
+
+	if !temp1.Equals(temp2) {
+		fmt.Println("*BUG* Pairing check failed *BUG*")
+	} else {
+		fmt.Println("Pairing verified correctly")
+	}
+}
+
+// Membership proof
+func Test2() {
+	// In a real application, generate this once and publish it
+	params := pbc.GenerateA(160, 512)
+
+	pairing := params.NewPairing()
+
+	// Initialize group elements. pbc automatically handles garbage collection.
+	g := pairing.NewG1().Rand()
+	h := pairing.NewG2().Rand()
+
+	privKey := pairing.NewZr().Rand()
+	pubKey_1 := pairing.NewG1().PowZn(g, privKey)
+	pubKey_2 := pairing.NewG2().PowZn(h, privKey)
+
+	Acc := accumulator.NewAccumulator(pubKey_1)
+
+	elements := make([]*pbc.Element, 9)
+	for i := range elements {
+		elements[i] = pairing.NewZr().Rand()
+	}
+	Acc.AddElementsWithKey(elements, privKey, pairing)
+
+	u_priv := pairing.NewZr().Rand()
+	Wit := accumulator.NewWitness(Acc.Value(), u_priv, nil)
+
+	Acc.AddElementWithKey(u_priv, privKey, pairing)
+
+	Wit.SetAccumulator(Acc)
+
+	if pairing.NewG2().Add(pubKey_2, pairing.NewG2().PowZn(h, u_priv)).Equals(pairing.NewG2().PowZn(h, pairing.NewZr().Add(privKey, u_priv))) {
+		fmt.Println("succ1")
+	}
+
+	if pairing.NewG1().PowZn(Wit.Value(), pairing.NewZr().Add(privKey, u_priv)).Equals(Acc.Value()) {
+		fmt.Println("succ2")
+	}
+
+	if accumulator.VerifyWitness(Wit, Acc, h, pubKey_2, u_priv, pairing) {
+		fmt.Println("  Witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+
+}
+
+// Add/delete members
+func Test3() {
+	// In a real application, generate this once and publish it
+	params := pbc.GenerateA(160, 512)
+
+	pairing := params.NewPairing()
+
+	// Initialize group elements. pbc automatically handles garbage collection.
+	g := pairing.NewG1().Rand()
+	h := pairing.NewG2().Rand()
+
+	privKey := pairing.NewZr().Rand()
+	pubKey_1 := pairing.NewG1().PowZn(g, privKey)
+	pubKey_2 := pairing.NewG2().PowZn(h, privKey)
+
+	Acc := accumulator.NewAccumulator(pubKey_1)
+
+	deleteEle := pairing.NewZr().Rand()
+	Acc.AddElementWithKey(deleteEle, privKey, pairing)
+
+	for i := 0; i < 9; i++ {
+		Acc.AddElementWithKey(pairing.NewZr().Rand(), privKey, pairing)
+	}
+
+	u_priv := pairing.NewZr().Rand()
+	Wit := accumulator.NewWitness(Acc.Value(), u_priv, nil)
+
+	Acc.AddElementWithKey(u_priv, privKey, pairing)
+
+	Wit.SetAccumulator(Acc)
+
+	if accumulator.VerifyWitness(Wit, Acc, h, pubKey_2, u_priv, pairing) {
+		fmt.Println("  Init witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Init witness check failed *BUG*")
+	}
+
+	// Delete Element
+	Acc.DeleteElementWithKey(deleteEle, privKey, pairing)
+	Wit.DeleteElementForWitness(deleteEle, u_priv, Acc, pairing)
+	Wit.SetAccumulator(Acc)
+
+	if accumulator.VerifyWitness(Wit, Acc, h, pubKey_2, u_priv, pairing) {
+		fmt.Println("  Witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+
+	// Add element
+	newEle := pairing.NewZr().Rand()
+
+	Acc.AddElementWithKey(newEle, privKey, pairing)
+	Wit.AddElementForWitness(newEle, u_priv, pairing)
+	Wit.SetAccumulator(Acc)
+
+	if accumulator.VerifyWitness(Wit, Acc, h, pubKey_2, u_priv, pairing) {
+		fmt.Println("  Witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+
+	// fmt.Println("invert test start")
+
+	// sb1 := pairing.NewZr().Sub(deleteEle, u_priv)
+	// g1_sb1 := pairing.NewG1().SetBytes(g.Bytes())
+	// g1_sb1.PowZn(g1_sb1, sb1)
+
+	// g2_isb1 := pairing.NewG2().SetBytes(h.Bytes())
+	// g2_isb1.PowZn(g2_isb1, pairing.NewZr().Invert(sb1))
+
+	// temp1 := pairing.NewGT().Pair(g1_sb1, g2_isb1)
+	// temp2 := pairing.NewGT().Pair(g, h)
+	// if temp1.Equals(temp2) {
+	// 	fmt.Println("  Invert verified correctly")
+	// } else {
+	// 	fmt.Println("  *BUG* Invert check failed *BUG*")
+	// }
+
+	// fmt.Println("invert test end")
+}
+
+// 成员证明 —— Hash 版本
+func Test4() {
+	// In a real application, generate this once and publish it
+	params := pbc.GenerateA(160, 512)
+
+	pairing := params.NewPairing()
+
+	// Initialize group elements. pbc automatically handles garbage collection.
+	g := pairing.NewG1().Rand()
+	h := pairing.NewG2().Rand()
+
+	privKey := pairing.NewZr().Rand()
+	pubKey_1 := pairing.NewG1().PowZn(g, privKey)
+	pubKey_2 := pairing.NewG2().PowZn(h, privKey)
+
+	Acc := accumulator.NewAccumulator(pubKey_1)
+
+	// Generate key pairs
+	const size = 10
+	curve := ring.Secp256k1()
+	pris := make([]types.Scalar, size)
+	pubs := make([]types.Point, size)
+	for i := 0; i < size; i++ {
+		priv := curve.NewRandomScalar()
+		// fmt.Printf("Size of priv: %d bytes\n", int64(reflect.TypeOf(priv).Size()))
+		pris[i] = priv
+		pubs[i] = curve.ScalarBaseMul(priv)
+	}
+
+	var list []accumulator.Content
+	for i := 0; i < size; i++ {
+		var a_hash = sha256.Sum256([]byte("Test Attribute"))
+		list = append(list, accumulator.AccumulatorContent{PublicKey: hex.EncodeToString(pubs[i].Encode()), Attributes: hex.EncodeToString(a_hash[:]), Role: "Test Role"})
+	}
+
+	for i := 0; i < size-1; i++ {
+		hash, _ := list[i].CalculateHash()
+		Acc.AddElementWithKey(pairing.NewZr().SetBytes(hash), privKey, pairing)
+	}
+
+	hash, _ := list[9].CalculateHash()
+	u_priv := pairing.NewZr().SetBytes(hash)
+	Wit := accumulator.NewWitness(Acc.Value(), u_priv, nil)
+
+	Acc.AddElementWithKey(u_priv, privKey, pairing)
+
+	Wit.SetAccumulator(Acc)
+
+	if accumulator.VerifyWitness(Wit, Acc, h, pubKey_2, u_priv, pairing) {
+		fmt.Println("  Witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+}
+
+func Demo() {
+
+	fmt.Println("0.Initialize system parameters")
+
+	// ecc -- 用于生成用户公私钥
+	curve := ring.Secp256k1()
+
+	// pairing -- 用于维护累加器
+	// the manager public-private key pair is generated together with the parameters
+	pp, privKey := accumulator.Setup(160, 512)
+	pairing := pp.Pairing
+	h := pp.H
+
+	sharedParams, _ := pp.MarshalJSON()
+	fmt.Println("public parameters:", string(sharedParams))
+	fmt.Println()
+
+	fmt.Println("1.Initialize accumulator")
+
+	pubKey_1 := pp.PK1
+	pubKey_2 := pp.PK2
+
+	Acc := pp.NewAccumulator()
+
+	fmt.Println("first accumulator:", hex.EncodeToString(pubKey_1.Bytes()))
+	fmt.Println("pk2 of the accumulator:", hex.EncodeToString(pubKey_2.Bytes()))
+	fmt.Println()
+
+	fmt.Println("2.Initialize 10 users and add them into the accumulator")
+
+	const size = 10
+
+	// initialize user public-private key pair
+	pris := make([]types.Scalar, size)
+	pubs := make([]types.Point, size)
+	for i := 0; i < size; i++ {
+		priv := curve.NewRandomScalar()
+		// fmt.Printf("Size of priv: %d bytes\n", int64(reflect.TypeOf(priv).Size()))
+		pris[i] = priv
+		pubs[i] = curve.ScalarBaseMul(priv)
+	}
+
+	// initialize user content
+	var list []accumulator.Content
+	for i := 0; i < size; i++ {
+		list = append(list, accumulator.AccumulatorContent{PublicKey: hex.EncodeToString(pubs[i].Encode()), Attributes: "Test Attributes", Role: "Test Role"})
+	}
+
+	// accumulate the content of tht first size-1 user to the accumulator
+	elements := make([]*pbc.Element, size-1)
+	for i := range elements {
+		hash, _ := list[i].CalculateHash()
+		elements[i] = pairing.NewZr().SetBytes(hash)
+		fmt.Println("element to be added to acc:", hex.EncodeToString(hash[:]))
+	}
+	Acc.AddElementsWithKey(elements, privKey, pairing)
+
+	// generate the witness for the last user, then update the accumulator
+	hash, _ := list[size-1].CalculateHash()
+	fmt.Println("element to be added to acc:", hex.EncodeToString(hash[:]))
+
+	u_priv := pairing.NewZr().SetBytes(hash)
+	Wit := accumulator.NewWitness(Acc.Value(), u_priv, nil)
+
+	Acc.AddElementWithKey(u_priv, privKey, pairing)
+
+	Wit.SetAccumulator(Acc)
+
+	fmt.Println("membership proof:", hex.EncodeToString(Wit.Value().Bytes()))
+	fmt.Println("accumulator:", hex.EncodeToString(Acc.Value().Bytes()))
+	fmt.Println()
+
+	fmt.Println("3.Verify the user info with accumulator")
+
+	// the verifier only receives the encoded parameters, accumulator and witness
+	sharedAcc, _ := Acc.MarshalBinary()
+	sharedWit, _ := Wit.MarshalJSON()
+	fmt.Println("accumulator:", hex.EncodeToString(sharedAcc))
+	fmt.Println("witness:", string(sharedWit))
+
+	verifierParams, err := accumulator.LoadPublicParams(sharedParams)
+	if err != nil {
+		fmt.Println("  *BUG* Loading public parameters failed *BUG*", err)
+		return
+	}
+	verifierAcc, err := verifierParams.DecodeAccumulator(sharedAcc)
+	if err != nil {
+		fmt.Println("  *BUG* Decoding accumulator failed *BUG*", err)
+		return
+	}
+	verifierWit, err := verifierParams.DecodeWitness(sharedWit)
+	if err != nil {
+		fmt.Println("  *BUG* Decoding witness failed *BUG*", err)
+		return
+	}
+	if accumulator.VerifyWitness(verifierWit, verifierAcc, verifierParams.H, verifierParams.PK2, verifierWit.Element(), verifierParams.Pairing) {
+		fmt.Println("  Witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+	fmt.Println()
+
+	fmt.Println("4.Add two users")
+
+	// info of the first new user
+	new_priv_1 := curve.NewRandomScalar()
+	new_pub_1 := curve.ScalarBaseMul(new_priv_1)
+	new_accC_1 := accumulator.AccumulatorContent{PublicKey: hex.EncodeToString(new_pub_1.Encode()), Attributes: "Test Attributes", Role: "Test Role"}
+
+	hash, _ = new_accC_1.CalculateHash()
+	new_u_priv_1 := pairing.NewZr().SetBytes(hash)
+	fmt.Println("user info 1", hex.EncodeToString(new_u_priv_1.Bytes()))
+
+	// info for the second new user
+	new_priv_2 := curve.NewRandomScalar()
+	new_pub_2 := curve.ScalarBaseMul(new_priv_2)
+	new_accC_2 := accumulator.AccumulatorContent{PublicKey: hex.EncodeToString(new_pub_2.Encode()), Attributes: "Test Attributes", Role: "Test Role"}
+
+	hash, _ = new_accC_2.CalculateHash()
+	new_u_priv_2 := pairing.NewZr().SetBytes(hash)
+	fmt.Println("user info 2:", hex.EncodeToString(new_u_priv_2.Bytes()))
+
+	// add the info of the two new users to accumulator and publish the update
+	addRecord := Acc.UpdateWithKey([]*pbc.Element{new_u_priv_1, new_u_priv_2}, nil, privKey, pairing)
+	fmt.Println("acc_new (after adding user 1):", hex.EncodeToString(addRecord.Steps[0].Bytes()))
+	fmt.Println("acc_new (after adding user 2):", hex.EncodeToString(Acc.Value().Bytes()))
+
+	// update user witness with the published record, no manager key needed
+	if err := Wit.ApplyUpdates([]*accumulator.UpdateRecord{addRecord}); err != nil {
+		fmt.Println("  *BUG* Witness update failed *BUG*", err)
+	}
+	fmt.Println("Member proof information after adding new users:", hex.EncodeToString(Wit.Value().Bytes()))
+
+	if accumulator.VerifyWitness(Wit, Acc, h, pubKey_2, u_priv, pairing) {
+		fmt.Println("  Witness verified correctly (after adding new user)")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+	fmt.Println()
+
+	fmt.Println("5. Delete 2 users (user 4 and user 6)")
+
+	// Remove the 4th user
+	delete_ele_4 := list[4-1]
+	hash, _ = delete_ele_4.CalculateHash()
+	delete_u_priv_4 := pairing.NewZr().SetBytes(hash)
+	fmt.Println("Information of user 4 to be deleted:", hex.EncodeToString(delete_u_priv_4.Bytes()))
+
+	// Remove the 6th user
+	delete_ele_6 := list[6-1]
+	hash, _ = delete_ele_6.CalculateHash()
+	delete_u_priv_6 := pairing.NewZr().SetBytes(hash)
+	fmt.Println("Information of user 6 to be deleted:", hex.EncodeToString(delete_u_priv_6.Bytes()))
+
+	// Delete user information from Acc and publish the update
+	deleteRecord := Acc.UpdateWithKey(nil, []*pbc.Element{delete_u_priv_4, delete_u_priv_6}, privKey, pairing)
+	fmt.Println("Acc information after deleting user 4:", hex.EncodeToString(deleteRecord.Steps[0].Bytes()))
+	fmt.Println("Acc information after deleting user 6:", hex.EncodeToString(Acc.Value().Bytes()))
+
+	// Update Witness with the record as received from the manager
+	sharedRecord, _ := deleteRecord.MarshalBinary()
+	receivedRecord, err := pp.DecodeUpdateRecord(sharedRecord)
+	if err != nil {
+		fmt.Println("  *BUG* Decoding update record failed *BUG*", err)
+		return
+	}
+	if err := Wit.ApplyUpdates([]*accumulator.UpdateRecord{receivedRecord}); err != nil {
+		fmt.Println("  *BUG* Witness update failed *BUG*", err)
+	}
+	fmt.Println("Member proof information after deleting users:", hex.EncodeToString(Wit.Value().Bytes()))
+
+	if accumulator.VerifyWitness(Wit, Acc, h, pubKey_2, u_priv, pairing) {
+		fmt.Println("  Witness verified correctly (after deleting old user)")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+}
+
+func HelperTest() {
+
+	fmt.Println("0. Initialize system parameters")
+
+	// ecc -- Used to generate user public/private keys
+	curve := ring.Secp256k1()
+
+	// pairing -- Used to maintain the accumulator
+	// Administrator's public/private keys are generated with the parameters
+	pp, privKey := accumulator.Setup(160, 512)
+	pairing := pp.Pairing
+	h := pp.H
+
+	sharedParams, _ := pp.MarshalBinary()
+	fmt.Println("Public parameters:", hex.EncodeToString(sharedParams))
+
+	// A verifier rebuilds the same system from the shared encoding
+	if _, err := accumulator.LoadPublicParams(sharedParams); err != nil {
+		fmt.Println("  *BUG* Loading public parameters failed *BUG*", err)
+	}
+	fmt.Println()
+
+	fmt.Println("1. Initialize the accumulator")
+
+	pubKey_1 := pp.PK1
+	pubKey_2 := pp.PK2
+
+	Acc := pp.NewAccumulator()
+
+	fmt.Println("Initial accumulator:", hex.EncodeToString(pubKey_1.Bytes()))
+	fmt.Println("Accumulator pk2:", hex.EncodeToString(pubKey_2.Bytes()))
+	fmt.Println()
+
+	fmt.Println("2. Initialize user information (10 users) and add to the accumulator")
+
+	const size = 10
+
+	// Initialize user public/private keys
+	pris := make([]types.Scalar, size)
+	pubs := make([]types.Point, size)
+	for i := 0; i < size; i++ {
+		priv := curve.NewRandomScalar()
+		// fmt.Printf("Size of priv: %d bytes\n", int64(reflect.TypeOf(priv).Size()))
+		pris[i] = priv
+		pubs[i] = curve.ScalarBaseMul(priv)
+	}
+
+	// Initialize user content
+	var list []accumulator.Content
+	for i := 0; i < size; i++ {
+		var a_hash = sha256.Sum256([]byte("Test Attribute"))
+		list = append(list, accumulator.AccumulatorContent{PublicKey: hex.EncodeToString(pubs[i].Encode()), Attributes: hex.EncodeToString(a_hash[:]), Role: "Test Role"})
+	}
+
+	// Add the first (size-1) user contents to the accumulator
+	elements := make([]*pbc.Element, size-1)
+	for i := range elements {
+		hash, _ := list[i].CalculateHash()
+		elements[i] = pairing.NewZr().SetBytes(hash)
+		fmt.Println("Element to be added to the accumulator:", hex.EncodeToString(elements[i].Bytes()))
+	}
+	Acc.AddElementsWithKey(elements, privKey, pairing)
+
+	// Generate Witness for the last user's content and update the Accumulator
+	hash, _ := list[size-1].CalculateHash()
+	fmt.Println("Element to be added to the accumulator (current user):", hex.EncodeToString(hash[:]))
+
+	u_priv := pairing.NewZr().SetBytes(hash)
+	Wit := accumulator.NewWitness(Acc.Value(), u_priv, nil)
+
+	Acc.AddElementWithKey(u_priv, privKey, pairing)
+
+	Wit.SetAccumulator(Acc)
+
+	fmt.Println("Membership proof:", hex.EncodeToString(Wit.Value().Bytes()))
+	fmt.Println("Accumulator:", hex.EncodeToString(Acc.Value().Bytes()))
+	fmt.Println()
+
+	fmt.Println("3. Verify user information using the accumulator")
+
+	// temp1 := pairing.NewGT().Pair(Wit.Value(), pairing.NewG2().Add(pubKey_2, pairing.NewG2().PowZn(h, u_priv)))
+	// temp2 := pairing.NewGT().Pair(Acc.Value(), h)
+	fmt.Println("Accumulator:", hex.EncodeToString(Acc.Value().Bytes()))
+	fmt.Println("Accumulator pk2:", hex.EncodeToString(pubKey_2.Bytes()))
+	fmt.Println("Membership proof:", hex.EncodeToString(Wit.Value().Bytes()))
+	fmt.Println("User information:", hex.EncodeToString(u_priv.Bytes()))
+	fmt.Println("h:", hex.EncodeToString(h.Bytes()))
+	if accumulator.VerifyWitness(Wit, Acc, h, pubKey_2, u_priv, pairing) {
+		fmt.Println("  Witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+	fmt.Println()
+
+	fmt.Println("4. User directly obtains the witness under the current accumulator from the administrator")
+
+	// Obtain information of the 5th user
+	hash, _ = list[5].CalculateHash()
+	u_priv_5 := pairing.NewZr().SetBytes(hash)
+
+	Wit5 := Acc.EasyWayToGetWitness(u_priv_5, privKey, pairing)
+	if accumulator.VerifyWitness(Wit5, Acc, h, pubKey_2, u_priv_5, pairing) {
+		fmt.Println("  Witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+	fmt.Println()
+
+	fmt.Println("5. Add user")
+
+	// New user information
+	new_priv_1 := curve.NewRandomScalar()
+	new_pub_1 := curve.ScalarBaseMul(new_priv_1)
+	var a_hash = sha256.Sum256([]byte("Test Attribute"))
+	new_accC_1 := accumulator.AccumulatorContent{PublicKey: hex.EncodeToString(new_pub_1.Encode()), Attributes: hex.EncodeToString(a_hash[:]), Role: "Test Role"}
+
+	hash, _ = new_accC_1.CalculateHash()
+	new_u_priv_1 := pairing.NewZr().SetBytes(hash)
+	fmt.Println("New user 1 information:", hex.EncodeToString(new_u_priv_1.Bytes()))
+
+	// Add new user 1
+	Acc.AddElementWithKey(new_u_priv_1, privKey, pairing)
+	fmt.Println("Accumulator information after adding new user 1:", hex.EncodeToString(Acc.Value().Bytes()))
+
+	// Update the 5th user's Witness based on new user 1's information
+	Wit5.AddElementForWitness(new_u_priv_1, u_priv_5, pairing)
+	Wit5.SetAccumulator(Acc)
+	fmt.Println("Membership proof information after adding new user 1:", hex.EncodeToString(Wit5.Value().Bytes()))
+
+	if accumulator.VerifyWitness(Wit5, Acc, h, pubKey_2, u_priv_5, pairing) {
+		fmt.Println("  Witness verified correctly (after adding new user)")
+	} else {
+		fmt.Println("  *BUG* Witness check failed *BUG*")
+	}
+	fmt.Println()
+
+}
+
+// Non-membership proof
+func NonMembershipTest() {
+	pp, privKey := accumulator.Setup(160, 512)
+	pairing := pp.Pairing
+
+	Acc := pp.NewAccumulator()
+	elements := make([]*pbc.Element, 9)
+	for i := range elements {
+		elements[i] = pairing.NewZr().Rand()
+	}
+	Acc.AddElementsWithKey(elements, privKey, pairing)
+
+	// the revoked credential is not part of the accumulator
+	u_priv := pairing.NewZr().Rand()
+	Wit := Acc.NonMembershipWitnessWithKey(u_priv, privKey, pp.G, pairing)
+
+	if accumulator.VerifyNonMembership(Wit, Acc, pp.G, pp.H, pp.PK2, u_priv, pairing) {
+		fmt.Println("  Non-membership witness verified correctly")
+	} else {
+		fmt.Println("  *BUG* Non-membership witness check failed *BUG*")
+	}
+
+	// delete a member and add another one, then update the witness without the key
+	record := Acc.UpdateWithKey([]*pbc.Element{pairing.NewZr().Rand()}, elements[:1], privKey, pairing)
+	if err := Wit.ApplyUpdates([]*accumulator.UpdateRecord{record}); err != nil {
+		fmt.Println("  *BUG* Non-membership witness update failed *BUG*", err)
+	}
+	if accumulator.VerifyNonMembership(Wit, Acc, pp.G, pp.H, pp.PK2, u_priv, pairing) {
+		fmt.Println("  Non-membership witness verified correctly (after update)")
+	} else {
+		fmt.Println("  *BUG* Non-membership witness check failed *BUG*")
+	}
+
+	// a member cannot use the witness of another element
+	if accumulator.VerifyNonMembership(Wit, Acc, pp.G, pp.H, pp.PK2, elements[1], pairing) {
+		fmt.Println("  *BUG* Non-membership witness accepted for a member *BUG*")
+	} else {
+		fmt.Println("  Non-membership witness rejected for a member")
+	}
+
+	// once the element is added the witness can no longer be updated
+	record = Acc.UpdateWithKey([]*pbc.Element{u_priv}, nil, privKey, pairing)
+	if err := Wit.ApplyUpdates([]*accumulator.UpdateRecord{record}); err != accumulator.ErrElementAdded {
+		fmt.Println("  *BUG* Non-membership witness updated after adding the element *BUG*", err)
+	} else {
+		fmt.Println("  Non-membership witness invalidated by adding the element")
+	}
+}
+
+// Membership proof without revealing the element
+func ZeroKnowledgeTest() {
+	pp, privKey := accumulator.Setup(160, 512)
+	pairing := pp.Pairing
+
+	Acc := pp.NewAccumulator()
+	elements := make([]*pbc.Element, 10)
+	for i := range elements {
+		elements[i] = pairing.NewZr().Rand()
+	}
+	Acc.AddElementsWithKey(elements, privKey, pairing)
+	Wit := Acc.EasyWayToGetWitness(elements[3], privKey, pairing)
+
+	// the verifier only learns the commitment and the responses
+	prover := accumulator.NewMembershipProver(Wit, pairing)
+	commitment := prover.Commit()
+	challenge := accumulator.NewChallenge(pairing)
+	response, err := prover.Respond(challenge)
+	if err != nil {
+		fmt.Println("  *BUG* Responding to the challenge failed *BUG*", err)
+		return
+	}
+	if accumulator.VerifyMembershipProof(commitment, challenge, response, Acc, pp.H, pp.PK2, pairing) {
+		fmt.Println("  Membership proof verified correctly")
+	} else {
+		fmt.Println("  *BUG* Membership proof check failed *BUG*")
+	}
+
+	// the proof does not hold for a different accumulator
+	Acc.AddElementWithKey(pairing.NewZr().Rand(), privKey, pairing)
+	if accumulator.VerifyMembershipProof(commitment, challenge, response, Acc, pp.H, pp.PK2, pairing) {
+		fmt.Println("  *BUG* Membership proof accepted for another accumulator *BUG*")
+	} else {
+		fmt.Println("  Membership proof rejected for another accumulator")
+	}
+}
+
+// Anonymous signature by a member
+func MemberSignatureTest() {
+	pp, privKey := accumulator.Setup(160, 512)
+	pairing := pp.Pairing
+
+	Acc := pp.NewAccumulator()
+	elements := make([]*pbc.Element, 10)
+	for i := range elements {
+		elements[i] = pairing.NewZr().Rand()
+	}
+	Acc.AddElementsWithKey(elements, privKey, pairing)
+	Wit := Acc.EasyWayToGetWitness(elements[7], privKey, pairing)
+
+	msg := []byte("transfer 10 coins")
+	sig := accumulator.SignAsMember(msg, Wit, elements[7])
+
+	// the service receives the encoded signature
+	sharedSig, _ := sig.MarshalBinary()
+	fmt.Println("member signature:", hex.EncodeToString(sharedSig))
+	receivedSig, err := pp.DecodeMemberSignature(sharedSig)
+	if err != nil {
+		fmt.Println("  *BUG* Decoding member signature failed *BUG*", err)
+		return
+	}
+	if accumulator.VerifyMemberSignature(msg, receivedSig, Acc, pp) {
+		fmt.Println("  Member signature verified correctly")
+	} else {
+		fmt.Println("  *BUG* Member signature check failed *BUG*")
+	}
+	if accumulator.VerifyMemberSignature([]byte("transfer 1000 coins"), receivedSig, Acc, pp) {
+		fmt.Println("  *BUG* Member signature accepted for another message *BUG*")
+	} else {
+		fmt.Println("  Member signature rejected for another message")
+	}
+}
+
+// One vote per member and poll
+func LinkableTest() {
+	mgr := accumulator.NewManager(accumulator.SetupManagerKey(160, 512))
+	pp := mgr.Params()
+
+	// The voter enrolls with the public half of its linking key, and the
+	// manager issues the credential that binds the key to its element.
+	lk := accumulator.NewLinkingKey(pp)
+	voter := accumulator.AccumulatorContent{PublicKey: lk.PublicKey(), Role: "voter"}
+	for _, content := range []accumulator.AccumulatorContent{
+		{PublicKey: accumulator.NewLinkingKey(pp).PublicKey(), Role: "voter"},
+		voter,
+		{PublicKey: accumulator.NewLinkingKey(pp).PublicKey(), Role: "voter"},
+	} {
+		if _, err := mgr.Add(content); err != nil {
+			fmt.Println("  *BUG* Enrollment failed:", err, "*BUG*")
+			return
+		}
+	}
+	sigma, err := mgr.LinkingCredential(voter.PublicKey)
+	if err != nil {
+		fmt.Println("  *BUG* Credential issuance failed:", err, "*BUG*")
+		return
+	}
+	member, _ := mgr.MemberByPublicKey(voter.PublicKey)
+	cred, err := lk.Credential(member.Element, sigma, pp)
+	if err != nil {
+		fmt.Println("  *BUG* Credential rejected:", err, "*BUG*")
+		return
+	}
+	Wit, err := mgr.Witness(voter)
+	if err != nil {
+		fmt.Println("  *BUG* Witness failed:", err, "*BUG*")
+		return
+	}
+	Acc := mgr.Accumulator()
+
+	registry := accumulator.NewNullifierRegistry()
+	vote := func(poll, choice string) {
+		sig := accumulator.SignLinkable([]byte(choice), []byte(poll), Wit, cred, pp)
+		if !accumulator.VerifyLinkableSignature([]byte(choice), []byte(poll), sig, Acc, pp) {
+			fmt.Println("  *BUG* Linkable signature check failed *BUG*")
+			return
+		}
+		if err := registry.Register([]byte(poll), sig.Tag); err != nil {
+			fmt.Printf("  Vote %q in %q rejected: %v\n", choice, poll, err)
+			return
+		}
+		fmt.Printf("  Vote %q in %q accepted\n", choice, poll)
+	}
+	vote("poll 1", "yes")
+	vote("poll 1", "no")
+	vote("poll 2", "no")
+}
+
+func ManagerTest() {
+	mgr := accumulator.NewManager(accumulator.SetupManagerKey(160, 512))
+	alice := accumulator.AccumulatorContent{PublicKey: "alice", Attributes: "age=30", Role: "user"}
+	bob := accumulator.AccumulatorContent{PublicKey: "bob", Attributes: "age=40", Role: "admin"}
+
+	if _, err := mgr.Update([]accumulator.AccumulatorContent{alice, bob}, nil); err != nil {
+		fmt.Println("  *BUG* Enrollment failed:", err, "*BUG*")
+		return
+	}
+	if _, err := mgr.Add(alice); errors.Is(err, accumulator.ErrDuplicateMember) {
+		fmt.Println("  Second add of alice refused:", err)
+	} else {
+		fmt.Println("  *BUG* Duplicate add was accepted *BUG*")
+	}
+	eve := accumulator.AccumulatorContent{PublicKey: "eve"}
+	if _, err := mgr.Delete(eve); errors.Is(err, accumulator.ErrUnknownMember) {
+		fmt.Println("  Deletion of eve refused:", err)
+	} else {
+		fmt.Println("  *BUG* Unknown deletion was accepted *BUG*")
+	}
+
+	wit, err := mgr.Witness(bob)
+	if err != nil {
+		fmt.Println("  *BUG* Witness issuance failed:", err, "*BUG*")
+		return
+	}
+	if _, err := mgr.DeletePublicKey("alice"); err != nil {
+		fmt.Println("  *BUG* Deletion of alice failed:", err, "*BUG*")
+		return
+	}
+	records, _ := mgr.History().RecordsSince(wit.Epoch())
+	if err := wit.ApplyUpdates(records); err != nil {
+		fmt.Println("  *BUG* Witness update failed:", err, "*BUG*")
+		return
+	}
+	pp := mgr.Params()
+	fmt.Printf("  %d member(s) at epoch %d, alice present: %v, bob's witness valid: %v\n",
+		mgr.Count(), mgr.Accumulator().Epoch(), mgr.Contains(alice),
+		accumulator.VerifyWitness(wit, mgr.Accumulator(), pp.H, pp.PK2, wit.Element(), pp.Pairing))
+	mgr.Range(func(m *accumulator.Member) bool {
+		fmt.Printf("  member %s (%s)\n", m.Content.PublicKey, m.Content.Role)
+		return true
+	})
+}
+
+func SubscriptionTest() {
+	mgr := accumulator.NewManager(accumulator.SetupManagerKey(160, 512))
+	pp := mgr.Params()
+	alice := accumulator.AccumulatorContent{PublicKey: "alice"}
+	mgr.Add(alice)
+	wit, _ := mgr.Witness(alice)
+	member, err := accumulator.NewMemberClient(pp, alice, wit)
+	if err != nil {
+		fmt.Println("  *BUG* Member client failed:", err, "*BUG*")
+		return
+	}
+
+	// The member follows the updates instead of being handed each change.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, err := mgr.History().Subscribe(ctx, member.Epoch())
+	if err != nil {
+		fmt.Println("  *BUG* Subscribe failed:", err, "*BUG*")
+		return
+	}
+	done := make(chan error)
+	go func() { done <- member.Follow(ctx, updates) }()
+
+	for _, pk := range []string{"bob", "carol", "dave"} {
+		mgr.Add(accumulator.AccumulatorContent{PublicKey: pk})
+	}
+	mgr.DeletePublicKey("carol")
+	mgr.DeletePublicKey("alice")
+	if err := <-done; !errors.Is(err, accumulator.ErrWitnessRevoked) {
+		fmt.Println("  *BUG* Revocation not detected:", err, "*BUG*")
+		return
+	}
+	last := member.Witness()
+	acc, _ := mgr.History().ValueAt(last.Epoch())
+	fmt.Printf("  Witness followed the updates to epoch %d (valid: %v) and detected the revocation at epoch %d\n",
+		last.Epoch(), accumulator.VerifyWitness(last, acc, pp.H, pp.PK2, last.Element(), pp.Pairing), last.Epoch()+1)
+}
+
+func RotationTest() {
+	mgr := accumulator.NewManager(accumulator.SetupManagerKey(160, 512))
+	oldParams := mgr.Params()
+	for _, pk := range []string{"alice", "bob", "carol"} {
+		mgr.Add(accumulator.AccumulatorContent{PublicKey: pk})
+	}
+	alice, _ := mgr.MemberByPublicKey("alice")
+	wit, _ := mgr.Witness(alice.Content)
+	member, _ := accumulator.NewMemberClient(oldParams, alice.Content, wit)
+
+	rot, err := mgr.Rotate(mgr.Key().Next())
+	if err != nil {
+		fmt.Println("  *BUG* Rotation failed:", err, "*BUG*")
+		return
+	}
+	// Holders of the old parameters learn the new ones from the record.
+	pp, err := rot.Verify(oldParams)
+	if err != nil || !pp.PK2.Equals(mgr.Params().PK2) {
+		fmt.Println("  *BUG* Rotation record does not verify:", err, "*BUG*")
+		return
+	}
+	if accumulator.VerifyWitness(wit, mgr.Accumulator(), pp.H, pp.PK2, wit.Element(), pp.Pairing) {
+		fmt.Println("  *BUG* Old witness is valid under the new key *BUG*")
+	}
+	issued := 0
+	mgr.RangeWitnesses(func(m *accumulator.Member, w *accumulator.Witness) bool {
+		if m.Content.PublicKey == "alice" {
+			err = member.Rotate(rot, w)
+		}
+		if accumulator.VerifyWitness(w, mgr.Accumulator(), pp.H, pp.PK2, m.Element, pp.Pairing) {
+			issued++
+		}
+		return true
+	})
+	if err != nil {
+		fmt.Println("  *BUG* Member did not accept the rotation:", err, "*BUG*")
+		return
+	}
+	fmt.Printf("  Key rotated at epoch %d, %d of %d witnesses re-issued, member at epoch %d\n",
+		rot.Epoch, issued, mgr.Count(), member.Epoch())
+}
+
+// Witnesses on an asymmetric curve, where G1 and G2 differ
+func AsymmetricTest() {
+	curve, _ := accumulator.CurveFor(accumulator.CurveF, accumulator.Security80)
+	pp, privKey, err := accumulator.SetupCurve(curve)
+	if err != nil {
+		fmt.Println("  *BUG* Type F setup failed:", err, "*BUG*")
+		return
+	}
+	pairing := pp.Pairing
+	fmt.Printf("  Curve type %s, symmetric: %v\n", pp.CurveType(), pairing.IsSymmetric())
+
+	Acc := pp.NewAccumulator()
+	elements := make([]*pbc.Element, 5)
+	for i := range elements {
+		elements[i] = pairing.NewZr().Rand()
+	}
+	Acc.AddElementsWithKey(elements, privKey, pairing)
+	Wit := Acc.EasyWayToGetWitness(elements[1], privKey, pairing)
+	record := Acc.UpdateWithKey([]*pbc.Element{pairing.NewZr().Rand()}, elements[:1], privKey, pairing)
+	if err := Wit.ApplyUpdates([]*accumulator.UpdateRecord{record}); err != nil {
+		fmt.Println("  *BUG* Witness update failed on Type F *BUG*", err)
+		return
+	}
+	if accumulator.VerifyWitness(Wit, Acc, pp.H, pp.PK2, elements[1], pairing) {
+		fmt.Println("  Witness verified correctly on Type F (after update)")
+	} else {
+		fmt.Println("  *BUG* Witness check failed on Type F *BUG*")
+	}
+
+	// the encodings keep G1 and G2 apart
+	data, _ := pp.MarshalJSON()
+	verifierParams, err := accumulator.LoadPublicParams(data)
+	if err != nil {
+		fmt.Println("  *BUG* Decoding Type F params failed *BUG*", err)
+		return
+	}
+	sig := accumulator.SignAsMember([]byte("hello"), Wit, elements[1])
+	sharedSig, _ := sig.MarshalBinary()
+	receivedSig, err := verifierParams.DecodeMemberSignature(sharedSig)
+	sharedAcc, _ := Acc.MarshalBinary()
+	receivedAcc, err2 := verifierParams.DecodeAccumulator(sharedAcc)
+	if err != nil || err2 != nil {
+		fmt.Println("  *BUG* Decoding on Type F failed *BUG*", err, err2)
+		return
+	}
+	if accumulator.VerifyMemberSignature([]byte("hello"), receivedSig, receivedAcc, verifierParams) {
+		fmt.Println("  Member signature verified correctly on Type F")
+	} else {
+		fmt.Println("  *BUG* Member signature check failed on Type F *BUG*")
+	}
+
+	nonMember := pairing.NewZr().Rand()
+	NonWit := Acc.NonMembershipWitnessWithKey(nonMember, privKey, pp.G, pairing)
+	if accumulator.VerifyNonMembership(NonWit, Acc, pp.G, pp.H, pp.PK2, nonMember, pairing) {
+		fmt.Println("  Non-membership witness verified correctly on Type F")
+	} else {
+		fmt.Println("  *BUG* Non-membership witness check failed on Type F *BUG*")
+	}
+}
@@ -0,0 +1,123 @@
+// Command grpc runs the gRPC manager service in process over an in-memory
+// connection and walks a member through enrollment, a membership proof and
+// the update stream.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/neucc1997/Accumulator"
+	"github.com/neucc1997/Accumulator/rpc"
+)
+
+func main() {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	// Only the operator holding the token may enroll, revoke and hand out
+	// witnesses; anyone may read the state and verify.
+	const token = "operator-token"
+	m := accumulator.NewManager(accumulator.SetupManagerKey(160, 512))
+	rpc.RegisterAccumulatorServiceServer(srv, rpc.NewService(m, rpc.WithAuthorizer(rpc.BearerToken(token))))
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	client := rpc.NewAccumulatorServiceClient(conn)
+	ctx := context.Background()
+	operator := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	msg, err := client.GetParams(ctx, &rpc.GetParamsRequest{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	pp, err := rpc.ToParams(msg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, pk := range []string{"alice", "bob", "carol"} {
+		if _, err := client.Enroll(operator, &rpc.EnrollRequest{Member: &rpc.Member{PublicKey: pk, Role: "user"}}); err != nil {
+			log.Fatal(err)
+		}
+	}
+	_, err = client.Enroll(ctx, &rpc.EnrollRequest{Member: &rpc.Member{PublicKey: "mallory"}})
+	if status.Code(err) == codes.Unauthenticated {
+		fmt.Println("  Enrollment without the token refused:", status.Convert(err).Message())
+	} else {
+		fmt.Println("  *BUG* Enrollment without the token was not refused *BUG*")
+	}
+	_, err = client.Enroll(operator, &rpc.EnrollRequest{Member: &rpc.Member{PublicKey: "bob"}})
+	if status.Code(err) == codes.AlreadyExists {
+		fmt.Println("  Second enrollment of bob refused:", status.Convert(err).Message())
+	} else {
+		fmt.Println("  *BUG* Duplicate enrollment was accepted *BUG*")
+	}
+
+	witMsg, err := client.IssueWitness(operator, &rpc.IssueWitnessRequest{PublicKey: "bob"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	wit, err := rpc.ToWitness(pp, witMsg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Prove membership without revealing the element.
+	sig := accumulator.SignAsMember([]byte("hello"), wit, wit.Element())
+	proof := rpc.FromSignature(sig, []byte("hello"), wit.Epoch())
+	resp, err := client.Verify(ctx, &rpc.VerifyRequest{Subject: &rpc.VerifyRequest_Proof{Proof: proof}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("  Membership proof at epoch %d: valid %v, current %v\n", resp.Epoch, resp.Valid, resp.Current)
+
+	// Follow the revocation of alice on the update stream.
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.StreamUpdates(streamCtx, &rpc.StreamUpdatesRequest{SinceEpoch: wit.Epoch()})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := client.Revoke(operator, &rpc.RevokeRequest{PublicKey: "alice"}); err != nil {
+		log.Fatal(err)
+	}
+	recMsg, err := stream.Recv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	rec, err := rpc.ToRecord(pp, recMsg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := wit.ApplyUpdates([]*accumulator.UpdateRecord{rec}); err != nil {
+		fmt.Println("  *BUG* Witness update failed:", err, "*BUG*")
+		return
+	}
+	resp, err = client.Verify(ctx, &rpc.VerifyRequest{Subject: &rpc.VerifyRequest_Witness{Witness: rpc.FromWitness(wit)}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("  Updated witness at epoch %d: valid %v, current %v\n", resp.Epoch, resp.Valid, resp.Current)
+
+	state, err := client.GetState(ctx, &rpc.GetStateRequest{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("  %d member(s) at epoch %d\n", state.MemberCount, state.Accumulator.Epoch)
+}
@@ -0,0 +1,104 @@
+// Command threshold splits a manager key 2-of-3 and runs the share holders
+// in process: they add members, issue a witness and delete a member without
+// anyone holding the key.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Nik-U/pbc"
+
+	"github.com/neucc1997/Accumulator"
+)
+
+func main() {
+	mk := accumulator.SetupManagerKey(160, 512)
+	pp := mk.Params
+	tk, shares, err := accumulator.SplitManagerKey(mk, 2, 3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := tk.Validate(); err != nil {
+		fmt.Println("  *BUG* Public shares do not match pk2:", err, "*BUG*")
+		return
+	}
+	fmt.Printf("  Key split %d-of-%d\n", tk.Threshold, len(shares))
+
+	acc := pp.NewAccumulator()
+	var elements []*pbc.Element
+	for i := 0; i < 3; i++ {
+		e := pp.Pairing.NewZr().Rand()
+		// Any two share holders suffice for an addition.
+		partials := map[int]*pbc.Element{}
+		for _, ks := range shares[i%2 : i%2+2] {
+			partials[ks.Index] = ks.AddPartial(acc)
+		}
+		if _, err := tk.Add(acc, e, partials); err != nil {
+			fmt.Println("  *BUG* Threshold addition failed:", err, "*BUG*")
+			return
+		}
+		elements = append(elements, e)
+	}
+	fmt.Printf("  Added %d elements, epoch %d\n", len(elements), acc.Epoch())
+
+	// A cheating share holder is caught and named.
+	partials := map[int]*pbc.Element{1: shares[0].AddPartial(acc), 2: pp.Pairing.NewG1().Rand()}
+	if _, err := tk.Add(acc.Clone(), pp.Pairing.NewZr().Rand(), partials); errors.Is(err, accumulator.ErrInvalidPartial) {
+		fmt.Println("  Bad partial rejected:", err)
+	} else {
+		fmt.Println("  *BUG* Bad partial was accepted *BUG*")
+	}
+
+	wit, err := tk.Witness(acc, elements[1], invert(shares, acc.Value(), elements[1]))
+	if err != nil {
+		fmt.Println("  *BUG* Threshold witness failed:", err, "*BUG*")
+		return
+	}
+	fmt.Println("  Witness for element 1 issued at epoch", wit.Epoch())
+
+	rec, err := tk.Delete(acc, elements[0], invert(shares, acc.Value(), elements[0]))
+	if err != nil {
+		fmt.Println("  *BUG* Threshold deletion failed:", err, "*BUG*")
+		return
+	}
+	if err := wit.ApplyUpdates([]*accumulator.UpdateRecord{rec}); err != nil {
+		fmt.Println("  *BUG* Witness update failed:", err, "*BUG*")
+		return
+	}
+	if accumulator.VerifyWitness(wit, acc, pp.H, pp.PK2, elements[1], pp.Pairing) {
+		fmt.Println("  Element 0 deleted, witness of element 1 still valid at epoch", acc.Epoch())
+	} else {
+		fmt.Println("  *BUG* Witness invalid after deletion *BUG*")
+	}
+}
+
+// invert runs the inversion protocol among all share holders and returns
+// their partial results for base^(1/(e+key)). The inboxes stand in for the
+// private channels: each deal is delivered to its recipient only.
+func invert(shares []*accumulator.KeyShare, base, e *pbc.Element) []*accumulator.InversionPartial {
+	participants := make([]int, len(shares))
+	for i, ks := range shares {
+		participants[i] = ks.Index
+	}
+	inbox := make(map[int][]*accumulator.InversionDeal)
+	for _, ks := range shares {
+		deals, err := ks.DealInversion(participants)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, deal := range deals {
+			inbox[deal.To] = append(inbox[deal.To], deal)
+		}
+	}
+	partials := make([]*accumulator.InversionPartial, len(shares))
+	for i, ks := range shares {
+		partial, err := ks.InversionPartial(base, e, inbox[ks.Index])
+		if err != nil {
+			log.Fatal(err)
+		}
+		partials[i] = partial
+	}
+	return partials
+}
@@ -1,4 +1,4 @@
-module example.com/m/v2
+module github.com/neucc1997/Accumulator
 
 go 1.18
 
@@ -6,11 +6,17 @@ require (
 	github.com/Nik-U/pbc v0.0.0-20181205041846-3e516ca0c5d6
 	github.com/athanorlabs/go-dleq v0.1.0
 	github.com/neucc1997/ring-go v0.0.0-20240830093045-e1bbe82710e9
+	golang.org/x/crypto v0.20.0
+	google.golang.org/grpc v1.54.0
+	google.golang.org/protobuf v1.30.0
 )
 
 require (
 	filippo.io/edwards25519 v1.0.0 // indirect
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.1.0 // indirect
-	golang.org/x/crypto v0.20.0 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	golang.org/x/net v0.21.0 // indirect
 	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto v0.0.0-20230110181048-76db0878b65f // indirect
 )
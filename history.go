@@ -0,0 +1,171 @@
+package accumulator
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrUnknownEpoch  = errors.New("accumulator: epoch is not in the history")
+	ErrHistorySealed = errors.New("accumulator: history was sealed by a key rotation")
+)
+
+// History keeps every value of an accumulator since a starting epoch
+// together with the update record that produced it. It is safe for
+// concurrent use.
+type History struct {
+	mu      sync.RWMutex
+	start   *Accumulator    // value at the first epoch of the history
+	records []*UpdateRecord // records[i] leads to epoch start.epoch+i+1
+	updated chan struct{}   // closed and replaced by Append and Seal
+	sealed  bool
+}
+
+// NewHistory returns a history starting at the value and epoch of start.
+func NewHistory(start *Accumulator) *History {
+	return &History{start: start.Clone(), updated: make(chan struct{})}
+}
+
+// Append adds the next update. Its epoch must follow the latest epoch of
+// the history.
+func (hs *History) Append(rec *UpdateRecord) error {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if hs.sealed {
+		return ErrHistorySealed
+	}
+	if rec.Epoch != hs.latestEpoch()+1 {
+		return ErrMissingUpdate
+	}
+	hs.records = append(hs.records, rec)
+	close(hs.updated)
+	hs.updated = make(chan struct{})
+	return nil
+}
+
+// Seal ends the history: no record can be appended any more, and the
+// channels of Subscribe are closed once they delivered the last record.
+// Managers seal their history when they rotate the key.
+func (hs *History) Seal() {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if !hs.sealed {
+		hs.sealed = true
+		close(hs.updated)
+		hs.updated = make(chan struct{})
+	}
+}
+
+// Sealed reports whether the history was sealed.
+func (hs *History) Sealed() bool {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.sealed
+}
+
+func (hs *History) latestEpoch() uint64 {
+	return hs.start.epoch + uint64(len(hs.records))
+}
+
+// FirstEpoch returns the epoch the history starts at.
+func (hs *History) FirstEpoch() uint64 {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.start.epoch
+}
+
+// LatestEpoch returns the epoch of the newest value.
+func (hs *History) LatestEpoch() uint64 {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.latestEpoch()
+}
+
+// Latest returns the newest accumulator value.
+func (hs *History) Latest() *Accumulator {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.valueAt(hs.latestEpoch())
+}
+
+// ValueAt returns the accumulator as it was at epoch.
+func (hs *History) ValueAt(epoch uint64) (*Accumulator, error) {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	if epoch < hs.start.epoch || epoch > hs.latestEpoch() {
+		return nil, ErrUnknownEpoch
+	}
+	return hs.valueAt(epoch), nil
+}
+
+func (hs *History) valueAt(epoch uint64) *Accumulator {
+	if epoch == hs.start.epoch {
+		return hs.start.Clone()
+	}
+	rec := hs.records[epoch-hs.start.epoch-1]
+	return &Accumulator{value: rec.Value.Pairing().NewG1().Set(rec.Value), epoch: rec.Epoch}
+}
+
+// Record returns the update that led to epoch.
+func (hs *History) Record(epoch uint64) (*UpdateRecord, error) {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	if epoch <= hs.start.epoch || epoch > hs.latestEpoch() {
+		return nil, ErrUnknownEpoch
+	}
+	return hs.records[epoch-hs.start.epoch-1], nil
+}
+
+// RecordsSince returns the updates after epoch, the records a witness at
+// epoch needs to catch up with the latest value.
+func (hs *History) RecordsSince(epoch uint64) ([]*UpdateRecord, error) {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	if epoch < hs.start.epoch || epoch > hs.latestEpoch() {
+		return nil, ErrUnknownEpoch
+	}
+	since := hs.records[epoch-hs.start.epoch:]
+	return append([]*UpdateRecord(nil), since...), nil
+}
+
+// Subscribe returns a channel that delivers every update after fromEpoch in
+// order: first the records already in the history, then each new one as it
+// is appended. A subscriber that lost its connection resumes without gaps
+// by subscribing again from the epoch of the last record it received. The
+// channel is closed when ctx is done, or after the last record of a sealed
+// history. A slow reader delays only its own subscription.
+func (hs *History) Subscribe(ctx context.Context, fromEpoch uint64) (<-chan *UpdateRecord, error) {
+	if _, err := hs.RecordsSince(fromEpoch); err != nil {
+		return nil, err
+	}
+	ch := make(chan *UpdateRecord)
+	go func() {
+		defer close(ch)
+		next := fromEpoch
+		for {
+			hs.mu.RLock()
+			pending := hs.records[next-hs.start.epoch:]
+			updated := hs.updated
+			sealed := hs.sealed
+			hs.mu.RUnlock()
+			for _, rec := range pending {
+				select {
+				case ch <- rec:
+				case <-ctx.Done():
+					return
+				}
+				next = rec.Epoch
+			}
+			if sealed {
+				return
+			}
+			select {
+			case <-updated:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
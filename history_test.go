@@ -0,0 +1,114 @@
+package accumulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestHistoryLookups(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	acc := pp.NewAccumulator()
+	for i := 0; i < 3; i++ {
+		acc.UpdateWithKey(randElements(pairing, 1), nil, mk.secret, pairing)
+	}
+	// The history starts at epoch 3, as after a snapshot.
+	hs := NewHistory(acc)
+	values := map[uint64]*Accumulator{3: acc.Clone()}
+	for i := 0; i < 4; i++ {
+		rec := acc.UpdateWithKey(randElements(pairing, 1), nil, mk.secret, pairing)
+		if err := hs.Append(rec); err != nil {
+			t.Fatal(err)
+		}
+		values[rec.Epoch] = acc.Clone()
+	}
+	if hs.FirstEpoch() != 3 || hs.LatestEpoch() != 7 || !hs.Latest().Equals(acc) {
+		t.Fatalf("history from %d to %d", hs.FirstEpoch(), hs.LatestEpoch())
+	}
+
+	for epoch, want := range values {
+		got, err := hs.ValueAt(epoch)
+		if err != nil || !got.Equals(want) {
+			t.Errorf("ValueAt(%d): got %v, %v", epoch, got, err)
+		}
+		records, err := hs.RecordsSince(epoch)
+		if err != nil || uint64(len(records)) != 7-epoch {
+			t.Errorf("RecordsSince(%d): got %d records, %v", epoch, len(records), err)
+		}
+	}
+	if rec, err := hs.Record(5); err != nil || rec.Epoch != 5 || !rec.Value.Equals(values[5].Value()) {
+		t.Errorf("Record(5): got %v, %v", rec, err)
+	}
+
+	// Epochs before the start were pruned, later ones do not exist yet.
+	for _, epoch := range []uint64{0, 2, 8} {
+		if _, err := hs.ValueAt(epoch); !errors.Is(err, ErrUnknownEpoch) {
+			t.Errorf("ValueAt(%d): got %v, want ErrUnknownEpoch", epoch, err)
+		}
+		if _, err := hs.RecordsSince(epoch); !errors.Is(err, ErrUnknownEpoch) {
+			t.Errorf("RecordsSince(%d): got %v, want ErrUnknownEpoch", epoch, err)
+		}
+		if _, err := hs.Subscribe(context.Background(), epoch); !errors.Is(err, ErrUnknownEpoch) {
+			t.Errorf("Subscribe(%d): got %v, want ErrUnknownEpoch", epoch, err)
+		}
+	}
+	// The record that led to the first epoch is gone with the epochs before.
+	for _, epoch := range []uint64{3, 8} {
+		if _, err := hs.Record(epoch); !errors.Is(err, ErrUnknownEpoch) {
+			t.Errorf("Record(%d): got %v, want ErrUnknownEpoch", epoch, err)
+		}
+	}
+
+	rec := acc.UpdateWithKey(randElements(pairing, 1), nil, mk.secret, pairing)
+	acc.UpdateWithKey(randElements(pairing, 1), nil, mk.secret, pairing)
+	if err := hs.Append(acc.record(nil, nil)); !errors.Is(err, ErrMissingUpdate) {
+		t.Errorf("Append after a gap: got %v, want ErrMissingUpdate", err)
+	}
+	hs.Seal()
+	if err := hs.Append(rec); !errors.Is(err, ErrHistorySealed) {
+		t.Errorf("Append to a sealed history: got %v, want ErrHistorySealed", err)
+	}
+	if _, err := hs.ValueAt(5); err != nil {
+		t.Errorf("ValueAt in a sealed history: %v", err)
+	}
+}
+
+func TestHistoryAfterSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	mk := setupTypeA(t)
+	m := recoverWithMembers(t, dir, mk, 3)
+	if err := m.WriteSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 3; i < 5; i++ {
+		if _, err := m.Add(AccumulatorContent{PublicKey: fmt.Sprint("member ", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := m.History().ValueAt(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	hs := m.History()
+	if hs.FirstEpoch() != 3 || hs.LatestEpoch() != 5 {
+		t.Fatalf("recovered history from %d to %d, want 3 to 5", hs.FirstEpoch(), hs.LatestEpoch())
+	}
+	if got, err := hs.ValueAt(4); err != nil || !got.Equals(want) {
+		t.Errorf("ValueAt(4) after recovery: got %v, %v", got, err)
+	}
+	if _, err := hs.ValueAt(2); !errors.Is(err, ErrUnknownEpoch) {
+		t.Errorf("ValueAt before the snapshot: got %v, want ErrUnknownEpoch", err)
+	}
+}
@@ -0,0 +1,191 @@
+package accumulator
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Nik-U/pbc"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	ErrKeyMismatch   = errors.New("accumulator: manager key does not match the public parameters")
+	ErrWrongPassword = errors.New("accumulator: wrong password or corrupted keystore")
+)
+
+// ManagerKey is the secret key of the accumulator manager together with the
+// public parameters it was generated for.
+type ManagerKey struct {
+	Params *PublicParams
+	secret *pbc.Element
+}
+
+// SetupManagerKey runs Setup and wraps the generated key.
+func SetupManagerKey(rbits, qbits uint32) *ManagerKey {
+	pp, key := Setup(rbits, qbits)
+	return &ManagerKey{Params: pp, secret: key}
+}
+
+// NewManagerKey wraps secret after checking that g^secret = pk1.
+func NewManagerKey(pp *PublicParams, secret *pbc.Element) (*ManagerKey, error) {
+	if !pp.Pairing.NewG1().PowZn(pp.G, secret).Equals(pp.PK1) {
+		return nil, ErrKeyMismatch
+	}
+	return &ManagerKey{Params: pp, secret: pp.Pairing.NewZr().Set(secret)}, nil
+}
+
+// Secret returns the manager key to pass to the *WithKey operations.
+// The returned element must not be modified.
+func (mk *ManagerKey) Secret() *pbc.Element {
+	return mk.secret
+}
+
+// Default scrypt cost of new keystores.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// keystoreJSON is the keystore file format. The manager key is sealed with
+// AES-256-GCM under a key derived from the password with scrypt; the binary
+// encoding of the public parameters is the additional data, so the key
+// cannot be moved to other parameters.
+type keystoreJSON struct {
+	Version    int             `json:"version"`
+	Params     json.RawMessage `json:"params"`
+	KDF        string          `json:"kdf"`
+	KDFParams  scryptParams    `json:"kdfparams"`
+	Cipher     string          `json:"cipher"`
+	Nonce      string          `json:"nonce"`
+	Ciphertext string          `json:"ciphertext"`
+}
+
+type scryptParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"`
+}
+
+func keystoreAEAD(password []byte, kp scryptParams) (cipher.AEAD, error) {
+	salt, err := hex.DecodeString(kp.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	key, err := scrypt.Key(password, salt, kp.N, kp.R, kp.P, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt returns the keystore encoding of mk protected by password.
+func (mk *ManagerKey) Encrypt(password []byte) ([]byte, error) {
+	params, err := mk.Params.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	aad, err := mk.Params.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	kp := scryptParams{N: scryptN, R: scryptR, P: scryptP, Salt: hex.EncodeToString(salt)}
+	aead, err := keystoreAEAD(password, kp)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(keystoreJSON{
+		Version:    encodingVersion,
+		Params:     params,
+		KDF:        "scrypt",
+		KDFParams:  kp,
+		Cipher:     "aes-256-gcm",
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, mk.secret.Bytes(), aad)),
+	}, "", "  ")
+}
+
+// DecryptManagerKey opens a keystore produced by Encrypt.
+func DecryptManagerKey(data, password []byte) (*ManagerKey, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, err
+	}
+	if ks.Version != encodingVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	if ks.KDF != "scrypt" || ks.Cipher != "aes-256-gcm" {
+		return nil, fmt.Errorf("%w: unsupported kdf %q or cipher %q", ErrInvalidEncoding, ks.KDF, ks.Cipher)
+	}
+	pp, err := LoadPublicParams(ks.Params)
+	if err != nil {
+		return nil, err
+	}
+	aad, err := pp.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(ks.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	ciphertext, err := hex.DecodeString(ks.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	aead, err := keystoreAEAD(password, ks.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrInvalidEncoding
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	secret := pp.Pairing.NewZr()
+	if err := setElementBytes(secret, plaintext); err != nil {
+		return nil, err
+	}
+	return NewManagerKey(pp, secret)
+}
+
+// SaveManagerKey encrypts mk with password and writes it to path, readable
+// by the owner only.
+func SaveManagerKey(path string, mk *ManagerKey, password []byte) error {
+	data, err := mk.Encrypt(password)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// LoadManagerKey reads and decrypts a keystore written by SaveManagerKey.
+func LoadManagerKey(path string, password []byte) (*ManagerKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptManagerKey(data, password)
+}
@@ -0,0 +1,77 @@
+package accumulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	mk := setupTypeA(t)
+	path := filepath.Join(t.TempDir(), "manager.key")
+	password := []byte("correct horse")
+	if err := SaveManagerKey(path, mk, password); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadManagerKey(path, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The loaded key has a pairing of its own.
+	if loaded.Params.Params != mk.Params.Params || !bytes.Equal(loaded.Params.PK2.Bytes(), mk.Params.PK2.Bytes()) ||
+		!bytes.Equal(loaded.secret.Bytes(), mk.secret.Bytes()) {
+		t.Error("loaded key differs from the saved one")
+	}
+	if _, err := LoadManagerKey(path, []byte("wrong horse")); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("wrong password: got %v, want ErrWrongPassword", err)
+	}
+}
+
+func TestKeystoreRejectsTampering(t *testing.T) {
+	mk := setupTypeA(t)
+	password := []byte("password")
+	data, err := mk.Encrypt(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := mk.Next().Params.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name   string
+		change func(ks *keystoreJSON)
+		want   error
+	}{
+		// The parameters are authenticated with the key.
+		{"other params", func(ks *keystoreJSON) { ks.Params = other }, ErrWrongPassword},
+		{"ciphertext", func(ks *keystoreJSON) {
+			b := []byte(ks.Ciphertext)
+			if b[0] == '0' {
+				b[0] = '1'
+			} else {
+				b[0] = '0'
+			}
+			ks.Ciphertext = string(b)
+		}, ErrWrongPassword},
+		{"nonce", func(ks *keystoreJSON) { ks.Nonce = ks.Nonce[2:] }, ErrInvalidEncoding},
+		{"cipher", func(ks *keystoreJSON) { ks.Cipher = "aes-128-cbc" }, ErrInvalidEncoding},
+		{"version", func(ks *keystoreJSON) { ks.Version++ }, ErrUnsupportedVersion},
+	}
+	for _, c := range cases {
+		var ks keystoreJSON
+		if err := json.Unmarshal(data, &ks); err != nil {
+			t.Fatal(err)
+		}
+		c.change(&ks)
+		tampered, err := json.Marshal(ks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := DecryptManagerKey(tampered, password); !errors.Is(err, c.want) {
+			t.Errorf("keystore with changed %s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+}
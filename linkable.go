@@ -0,0 +1,375 @@
+package accumulator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/Nik-U/pbc"
+)
+
+var (
+	ErrNullifierUsed     = errors.New("accumulator: nullifier already used in this context")
+	ErrInvalidCredential = errors.New("accumulator: linking credential does not match the element and key")
+)
+
+const (
+	linkableSignatureDomain = "accumulator/linkable-signature/v3"
+	nullifierBaseDomain     = "accumulator/nullifier-base/v3"
+	linkingBaseDomain       = "accumulator/linking-base/v1"
+)
+
+// A linkable signature carries the tag Z^k, where Z is derived from a
+// context such as a poll or a coupon batch and k is a secret of the
+// signer, its LinkingKey. The element of a member cannot serve as k: it is
+// the hash of the member's content and is published in every update
+// record, so anybody could compute the tag of every member.
+//
+// k is tied to the membership by a linking credential that the manager
+// issues on the element e and the public key K = U^k of the member:
+//
+//	sigma = (g * K)^(1/(e+key)), checked by e(sigma, h^e * pk2) = e(g * K, h)
+//
+// where U is a point of G1 hashed from g. The signature proves knowledge
+// of a witness and of a credential for the same e, randomized like the
+// witness of the membership proof, and that the tag uses the k of the
+// credential. A member always produces the same tag in the same context,
+// so a second use is detected by the tag alone, while tags of different
+// contexts or of different members cannot be linked to each other or to K
+// as long as the decisional Diffie-Hellman problem is hard in GT.
+//
+// The tag lives in GT rather than G1: on the symmetric Type A and E
+// pairings anybody could link tags B1^k and B2^k of G1 by checking
+// e(Tag1, B2) = e(B1, Tag2), and no pairing is known to act on GT.
+//
+// The manager issues the credential on the K its member enrolled with as
+// public key, see Manager.LinkingCredential, so that every member has one
+// credential and hence one tag per context.
+
+// LinkableSignature is a member signature that also carries the tag of the
+// signer in a context and proves that the tag is well formed.
+type LinkableSignature struct {
+	MemberSignature
+	SigmaBar *pbc.Element // randomized credential sigma^a
+	SBar     *pbc.Element // g^a * U^(a*k) / SigmaBar^e = SigmaBar^key
+	S3       *pbc.Element // rho3 + c * a
+	S4       *pbc.Element // rho4 + c * a * k
+	Tag      *pbc.Element // nullifier Z^k in GT
+}
+
+// LinkingKey is the secret k of a member for linkable signatures.
+type LinkingKey struct {
+	secret *pbc.Element
+	public *pbc.Element // U^k
+}
+
+// linkingBase returns the point U of G1 that carries the linking keys.
+func (pp *PublicParams) linkingBase() *pbc.Element {
+	h := sha256.New()
+	h.Write([]byte(linkingBaseDomain))
+	h.Write(pp.G.Bytes())
+	return pp.Pairing.NewG1().SetFromHash(h.Sum(nil))
+}
+
+// NewLinkingKey returns a fresh linking key. The member enrolls with its
+// PublicKey as the public key of its content to be issued a credential.
+func NewLinkingKey(pp *PublicParams) *LinkingKey {
+	k := randNonZero(pp.Pairing)
+	return &LinkingKey{secret: k, public: pp.Pairing.NewG1().PowZn(pp.linkingBase(), k)}
+}
+
+// PublicKey returns the hex encoding of K = U^k.
+func (lk *LinkingKey) PublicKey() string {
+	return hex.EncodeToString(lk.public.Bytes())
+}
+
+// MarshalBinary encodes the secret of the key. It must be stored as
+// carefully as the witness.
+func (lk *LinkingKey) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.bytes(lk.secret.Bytes())
+	return e.buf, nil
+}
+
+// DecodeLinkingKey decodes a key written by LinkingKey.MarshalBinary.
+func (pp *PublicParams) DecodeLinkingKey(data []byte) (*LinkingKey, error) {
+	d := newDecoder(data)
+	k := d.element(pp.Pairing.NewZr())
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	if k.Is0() {
+		return nil, ErrInvalidEncoding
+	}
+	return &LinkingKey{secret: k, public: pp.Pairing.NewG1().PowZn(pp.linkingBase(), k)}, nil
+}
+
+// decodeLinkingPublicKey decodes K from the public key of a content.
+func (pp *PublicParams) decodeLinkingPublicKey(publicKey string) (*pbc.Element, error) {
+	k := pp.Pairing.NewG1()
+	if err := decodeHexElement(k, publicKey); err != nil {
+		return nil, err
+	}
+	if k.Is0() {
+		return nil, ErrInvalidEncoding
+	}
+	return k, nil
+}
+
+// IssueLinkingCredential computes the credential (g * K)^(1/(e+key)) of
+// element for the linking public key K. Only the holder of the manager key
+// can compute it; it must issue one credential per element.
+func IssueLinkingCredential(element, publicKey *pbc.Element, key *pbc.Element, pp *PublicParams) *pbc.Element {
+	pairing := pp.Pairing
+	base := pairing.NewG1().Add(pp.G, publicKey)
+	exp := pairing.NewZr().Add(element, key)
+	return pairing.NewG1().PowZn(base, exp.Invert(exp))
+}
+
+// LinkingCredential is a credential together with the element and the
+// linking key it was issued on, everything SignLinkable needs besides the
+// witness.
+type LinkingCredential struct {
+	Sigma   *pbc.Element
+	element *pbc.Element
+	key     *LinkingKey
+}
+
+// Credential checks sigma, issued for element and the public key of lk,
+// against pk2 and returns the credential.
+func (lk *LinkingKey) Credential(element, sigma *pbc.Element, pp *PublicParams) (*LinkingCredential, error) {
+	pairing := pp.Pairing
+	if sigma.Is0() || !inSubgroup(sigma) {
+		return nil, ErrInvalidCredential
+	}
+	lhs := pairing.NewGT().Pair(sigma, pairing.NewG2().Add(pairing.NewG2().PowZn(pp.H, element), pp.PK2))
+	rhs := pairing.NewGT().Pair(pairing.NewG1().Add(pp.G, lk.public), pp.H)
+	if !lhs.Equals(rhs) {
+		return nil, ErrInvalidCredential
+	}
+	return &LinkingCredential{Sigma: sigma, element: element, key: lk}, nil
+}
+
+// nullifierBase hashes context to points B1 of G1 and B2 of G2 and returns
+// the base Z = e(B1, B2) in GT.
+func nullifierBase(context []byte, pairing *pbc.Pairing) *pbc.Element {
+	hash := func(group byte) []byte {
+		h := sha256.New()
+		h.Write([]byte(nullifierBaseDomain))
+		h.Write([]byte{group})
+		h.Write(context)
+		return h.Sum(nil)
+	}
+	return pairing.NewGT().Pair(pairing.NewG1().SetFromHash(hash(1)), pairing.NewG2().SetFromHash(hash(2)))
+}
+
+// Tag returns the tag of the key in context, the one its signatures carry.
+func (lk *LinkingKey) Tag(context []byte) *pbc.Element {
+	base := nullifierBase(context, lk.secret.Pairing())
+	return base.NewFieldElement().PowZn(base, lk.secret)
+}
+
+// SignLinkable signs msg as a member, bound to context. It extends
+// SignAsMember with the randomized credential cred and a proof that the
+// tag uses its linking key. wit must be a witness of the element of cred.
+func SignLinkable(msg, context []byte, wit *Witness, cred *LinkingCredential, pp *PublicParams) *LinkableSignature {
+	pairing := pp.Pairing
+	signer := &Witness{value: wit.value, element: cred.element, acc: wit.acc}
+	k := cred.key.secret
+	u := pp.linkingBase()
+	base := nullifierBase(context, pairing)
+
+	r := randNonZero(pairing)
+	a := randNonZero(pairing)
+	b := pairing.NewZr().Mul(a, k)
+	rho1 := pairing.NewZr().Rand()
+	rho2 := pairing.NewZr().Rand()
+	rho3 := pairing.NewZr().Rand()
+	rho4 := pairing.NewZr().Rand()
+	com := commitMembership(signer, r, rho1, rho2, pairing)
+
+	sigmaBar := pairing.NewG1().PowZn(cred.Sigma, a)
+	sBar := pairing.NewG1().Add(pairing.NewG1().PowZn(pp.G, a), pairing.NewG1().PowZn(u, b))
+	sBar.Sub(sBar, pairing.NewG1().PowZn(sigmaBar, cred.element))
+	t3 := pairing.NewG1().Add(pairing.NewG1().PowZn(pp.G, rho3), pairing.NewG1().PowZn(u, rho4))
+	t3.Sub(t3, pairing.NewG1().PowZn(sigmaBar, rho2))
+	tag := pairing.NewGT().PowZn(base, k)
+	t2 := pairing.NewGT().Div(pairing.NewGT().PowZn(tag, rho3), pairing.NewGT().PowZn(base, rho4))
+
+	sig := &LinkableSignature{SigmaBar: sigmaBar, SBar: sBar, Tag: tag}
+	c := linkableChallenge(msg, context, wit.acc.value, com, sig, t3, t2, pairing)
+	resp := respondMembership(signer, r, rho1, rho2, c, pairing)
+	sig.MemberSignature = MemberSignature{WBar: com.WBar, VBar: com.VBar, C: c, S1: resp.S1, S2: resp.S2}
+	sig.S3 = pairing.NewZr().Add(rho3, pairing.NewZr().Mul(c, a))
+	sig.S4 = pairing.NewZr().Add(rho4, pairing.NewZr().Mul(c, b))
+	return sig
+}
+
+// VerifyLinkableSignature checks that sig was made on msg in context by a
+// member of acc. Use a NullifierRegistry to reject a second use of the tag.
+func VerifyLinkableSignature(msg, context []byte, sig *LinkableSignature, acc *Accumulator, pp *PublicParams) bool {
+	pairing := pp.Pairing
+	if sig.Tag.Is1() || !inSubgroup(sig.Tag) ||
+		!checkRandomizedWitness(sig.WBar, sig.VBar, pp.H, pp.PK2, pairing) ||
+		!checkRandomizedWitness(sig.SigmaBar, sig.SBar, pp.H, pp.PK2, pairing) {
+		return false
+	}
+	// T = Acc^s1 / WBar^s2 / VBar^c
+	t := pairing.NewG1().Sub(pairing.NewG1().PowZn(acc.value, sig.S1), pairing.NewG1().PowZn(sig.WBar, sig.S2))
+	t.Sub(t, pairing.NewG1().PowZn(sig.VBar, sig.C))
+	// T3 = g^s3 * U^s4 / SigmaBar^s2 / SBar^c
+	u := pp.linkingBase()
+	t3 := pairing.NewG1().Add(pairing.NewG1().PowZn(pp.G, sig.S3), pairing.NewG1().PowZn(u, sig.S4))
+	t3.Sub(t3, pairing.NewG1().PowZn(sig.SigmaBar, sig.S2))
+	t3.Sub(t3, pairing.NewG1().PowZn(sig.SBar, sig.C))
+	// T2 = Tag^s3 / Z^s4
+	base := nullifierBase(context, pairing)
+	t2 := pairing.NewGT().Div(pairing.NewGT().PowZn(sig.Tag, sig.S3), pairing.NewGT().PowZn(base, sig.S4))
+	com := &MembershipCommitment{WBar: sig.WBar, VBar: sig.VBar, T: t}
+	return linkableChallenge(msg, context, acc.value, com, sig, t3, t2, pairing).Equals(sig.C)
+}
+
+func linkableChallenge(msg, context []byte, acc *pbc.Element, com *MembershipCommitment, sig *LinkableSignature, t3, t2 *pbc.Element, pairing *pbc.Pairing) *pbc.Element {
+	return hashToZr(pairing, linkableSignatureDomain, context, acc.Bytes(), com.WBar.Bytes(), com.VBar.Bytes(), com.T.Bytes(),
+		sig.SigmaBar.Bytes(), sig.SBar.Bytes(), t3.Bytes(), sig.Tag.Bytes(), t2.Bytes(), msg)
+}
+
+// MarshalBinary encodes the signature as the encoding of the member
+// signature with the length-prefixed SigmaBar, SBar, S3, S4 and tag
+// appended.
+func (sig *LinkableSignature) MarshalBinary() ([]byte, error) {
+	data, err := sig.MemberSignature.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	e := &encoder{buf: data}
+	for _, el := range []*pbc.Element{sig.SigmaBar, sig.SBar, sig.S3, sig.S4, sig.Tag} {
+		e.bytes(el.Bytes())
+	}
+	return e.buf, nil
+}
+
+type linkableSignatureJSON struct {
+	memberSignatureJSON
+	SigmaBar string `json:"sigmabar"`
+	SBar     string `json:"sbar"`
+	S3       string `json:"s3"`
+	S4       string `json:"s4"`
+	Tag      string `json:"tag"`
+}
+
+// MarshalJSON encodes the signature with the elements in hex.
+func (sig *LinkableSignature) MarshalJSON() ([]byte, error) {
+	return json.Marshal(linkableSignatureJSON{
+		memberSignatureJSON: memberSignatureJSON{
+			Version: encodingVersion,
+			WBar:    hex.EncodeToString(sig.WBar.Bytes()),
+			VBar:    hex.EncodeToString(sig.VBar.Bytes()),
+			C:       hex.EncodeToString(sig.C.Bytes()),
+			S1:      hex.EncodeToString(sig.S1.Bytes()),
+			S2:      hex.EncodeToString(sig.S2.Bytes()),
+		},
+		SigmaBar: hex.EncodeToString(sig.SigmaBar.Bytes()),
+		SBar:     hex.EncodeToString(sig.SBar.Bytes()),
+		S3:       hex.EncodeToString(sig.S3.Bytes()),
+		S4:       hex.EncodeToString(sig.S4.Bytes()),
+		Tag:      hex.EncodeToString(sig.Tag.Bytes()),
+	})
+}
+
+// DecodeLinkableSignature decodes a signature in either encoding into the
+// pairing of pp.
+func (pp *PublicParams) DecodeLinkableSignature(data []byte) (*LinkableSignature, error) {
+	sig := &LinkableSignature{
+		MemberSignature: MemberSignature{
+			WBar: pp.Pairing.NewG1(),
+			VBar: pp.Pairing.NewG1(),
+			C:    pp.Pairing.NewZr(),
+			S1:   pp.Pairing.NewZr(),
+			S2:   pp.Pairing.NewZr(),
+		},
+		SigmaBar: pp.Pairing.NewG1(),
+		SBar:     pp.Pairing.NewG1(),
+		S3:       pp.Pairing.NewZr(),
+		S4:       pp.Pairing.NewZr(),
+		Tag:      pp.Pairing.NewGT(),
+	}
+	fields := []*pbc.Element{sig.WBar, sig.VBar, sig.C, sig.S1, sig.S2, sig.SigmaBar, sig.SBar, sig.S3, sig.S4, sig.Tag}
+	if isJSON(data) {
+		var v linkableSignatureJSON
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		if v.Version != encodingVersion {
+			return nil, ErrUnsupportedVersion
+		}
+		for i, s := range []string{v.WBar, v.VBar, v.C, v.S1, v.S2, v.SigmaBar, v.SBar, v.S3, v.S4, v.Tag} {
+			if err := decodeHexElement(fields[i], s); err != nil {
+				return nil, err
+			}
+		}
+		return sig, nil
+	}
+	d := newDecoder(data)
+	for _, el := range fields {
+		d.element(el)
+	}
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// NullifierRegistry remembers the tags seen per context. It is safe for
+// concurrent use.
+type NullifierRegistry struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewNullifierRegistry returns an empty registry.
+func NewNullifierRegistry() *NullifierRegistry {
+	return &NullifierRegistry{seen: make(map[string]struct{})}
+}
+
+func nullifierKey(context []byte, tag *pbc.Element) string {
+	h := sha256.New()
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(context)))
+	h.Write(n[:])
+	h.Write(context)
+	h.Write(tag.Bytes())
+	return string(h.Sum(nil))
+}
+
+// Register records tag for context. It returns ErrNullifierUsed if the tag
+// was already registered in that context.
+func (r *NullifierRegistry) Register(context []byte, tag *pbc.Element) error {
+	key := nullifierKey(context, tag)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.seen[key]; ok {
+		return ErrNullifierUsed
+	}
+	r.seen[key] = struct{}{}
+	return nil
+}
+
+// Contains reports whether tag was registered for context.
+func (r *NullifierRegistry) Contains(context []byte, tag *pbc.Element) bool {
+	key := nullifierKey(context, tag)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.seen[key]
+	return ok
+}
+
+// Len returns the number of registered tags over all contexts.
+func (r *NullifierRegistry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.seen)
+}
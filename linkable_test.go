@@ -0,0 +1,168 @@
+package accumulator
+
+import (
+	"errors"
+	"testing"
+)
+
+// linkingMember enrolls a member whose public key is a fresh linking key
+// and returns its credential.
+func linkingMember(t *testing.T, m *Manager, role string) *LinkingCredential {
+	t.Helper()
+	pp := m.Params()
+	lk := NewLinkingKey(pp)
+	content := AccumulatorContent{PublicKey: lk.PublicKey(), Role: role}
+	if _, err := m.Add(content); err != nil {
+		t.Fatal(err)
+	}
+	sigma, err := m.LinkingCredential(content.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, _ := m.MemberByPublicKey(content.PublicKey)
+	cred, err := lk.Credential(member.Element, sigma, pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cred
+}
+
+func TestLinkableTags(t *testing.T) {
+	m := NewManager(setupTypeA(t))
+	pp := m.Params()
+	pairing := pp.Pairing
+	cred := linkingMember(t, m, "voter")
+	wit, err := m.Witness(AccumulatorContent{PublicKey: cred.key.PublicKey(), Role: "voter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := m.Accumulator()
+
+	first := SignLinkable([]byte("yes"), []byte("poll 1"), wit, cred, pp)
+	second := SignLinkable([]byte("no"), []byte("poll 1"), wit, cred, pp)
+	other := SignLinkable([]byte("no"), []byte("poll 2"), wit, cred, pp)
+	if !VerifyLinkableSignature([]byte("yes"), []byte("poll 1"), first, acc, pp) {
+		t.Fatal("linkable signature rejected")
+	}
+	if VerifyLinkableSignature([]byte("yes"), []byte("poll 2"), first, acc, pp) {
+		t.Error("linkable signature accepted in another context")
+	}
+	if VerifyLinkableSignature([]byte("no"), []byte("poll 1"), first, acc, pp) {
+		t.Error("linkable signature accepted for another message")
+	}
+	if len(first.Tag.Bytes()) != int(pairing.GTLength()) {
+		t.Errorf("tag has %d bytes, want an element of GT", len(first.Tag.Bytes()))
+	}
+	if !first.Tag.Equals(cred.key.Tag([]byte("poll 1"))) {
+		t.Error("tag is not the tag of the linking key")
+	}
+
+	registry := NewNullifierRegistry()
+	if err := registry.Register([]byte("poll 1"), first.Tag); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register([]byte("poll 1"), second.Tag); !errors.Is(err, ErrNullifierUsed) {
+		t.Errorf("second tag in the same context: got %v, want ErrNullifierUsed", err)
+	}
+	if other.Tag.Equals(first.Tag) {
+		t.Error("tags of different contexts are equal")
+	}
+	if err := registry.Register([]byte("poll 2"), other.Tag); err != nil {
+		t.Errorf("tag in another context: %v", err)
+	}
+}
+
+func TestLinkableTagsHideTheElement(t *testing.T) {
+	m := NewManager(setupTypeA(t))
+	pp := m.Params()
+	pairing := pp.Pairing
+	var creds []*LinkingCredential
+	for _, role := range []string{"a", "b", "c"} {
+		creds = append(creds, linkingMember(t, m, role))
+	}
+	wit, err := m.Witness(AccumulatorContent{PublicKey: creds[1].key.PublicKey(), Role: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := []byte("poll")
+	sig := SignLinkable([]byte("yes"), ctx, wit, creds[1], pp)
+	if !VerifyLinkableSignature([]byte("yes"), ctx, sig, m.Accumulator(), pp) {
+		t.Fatal("linkable signature rejected")
+	}
+
+	// Everything the records publish about the members: their elements and
+	// the public keys of their contents.
+	base := nullifierBase(ctx, pairing)
+	for _, member := range m.Members() {
+		if pairing.NewGT().PowZn(base, member.Element).Equals(sig.Tag) {
+			t.Error("tag is Z^e of a published element")
+		}
+		k, err := pp.decodeLinkingPublicKey(member.Content.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pairing.NewGT().Pair(k, pp.H).Equals(sig.Tag) {
+			t.Error("tag is the pairing of a published public key")
+		}
+	}
+	for i, cred := range creds {
+		if i != 1 && cred.key.Tag(ctx).Equals(sig.Tag) {
+			t.Errorf("tag equals the tag of member %d", i)
+		}
+	}
+	if sig.SigmaBar.Equals(creds[1].Sigma) || sig.WBar.Equals(wit.value) {
+		t.Error("signature carries the credential or witness unrandomized")
+	}
+}
+
+func TestLinkableRejectsForeignCredential(t *testing.T) {
+	m := NewManager(setupTypeA(t))
+	pp := m.Params()
+	alice := linkingMember(t, m, "a")
+	bob := linkingMember(t, m, "b")
+	wit, err := m.Witness(AccumulatorContent{PublicKey: alice.key.PublicKey(), Role: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The credential of bob with the witness of alice proves nothing about
+	// a single element, nor does a credential for a key nobody enrolled.
+	mixed := &LinkingCredential{Sigma: bob.Sigma, element: alice.element, key: bob.key}
+	if sig := SignLinkable([]byte("m"), []byte("c"), wit, mixed, pp); VerifyLinkableSignature([]byte("m"), []byte("c"), sig, m.Accumulator(), pp) {
+		t.Error("signature with the credential of another member accepted")
+	}
+	other := NewLinkingKey(pp)
+	if _, err := other.Credential(alice.element, alice.Sigma, pp); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("credential of alice for another key: got %v, want ErrInvalidCredential", err)
+	}
+	forged := &LinkingCredential{Sigma: alice.Sigma, element: alice.element, key: other}
+	if sig := SignLinkable([]byte("m"), []byte("c"), wit, forged, pp); VerifyLinkableSignature([]byte("m"), []byte("c"), sig, m.Accumulator(), pp) {
+		t.Error("signature with a key that has no credential accepted")
+	}
+
+	if _, err := m.Add(AccumulatorContent{PublicKey: "not a point"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.LinkingCredential("not a point"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("credential for a public key that is no point: got %v, want ErrInvalidEncoding", err)
+	}
+	if _, err := m.LinkingCredential(NewLinkingKey(pp).PublicKey()); !errors.Is(err, ErrUnknownMember) {
+		t.Errorf("credential for a key that is not enrolled: got %v, want ErrUnknownMember", err)
+	}
+}
+
+func TestLinkingKeyRoundTrip(t *testing.T) {
+	pp := setupTypeA(t).Params
+	lk := NewLinkingKey(pp)
+	data, err := lk.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := pp.DecodeLinkingKey(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.PublicKey() != lk.PublicKey() {
+		t.Error("decoded linking key differs")
+	}
+}
@@ -1,15 +0,0 @@
-package main  
-
-import (     
-	"fmt"
-)
-
-func main() {     
-	Test1()
-	Test2()
-	Test3()
-	Test4()
-	Demo()
-	fmt.Println("=================================================")
-	HelperTest()
-}
\ No newline at end of file
@@ -0,0 +1,323 @@
+package accumulator
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Nik-U/pbc"
+)
+
+var (
+	ErrDuplicateMember = errors.New("accumulator: member is already accumulated")
+	ErrUnknownMember   = errors.New("accumulator: member is not accumulated")
+)
+
+// MemberError reports an add or delete refused by a Manager.
+type MemberError struct {
+	Op        Op
+	Element   string // hex encoding of the element
+	PublicKey string
+	Err       error // ErrDuplicateMember or ErrUnknownMember
+}
+
+func (e *MemberError) Error() string {
+	if e.Element == "" {
+		return fmt.Sprintf("%s public key %q: %v", e.Op, e.PublicKey, e.Err)
+	}
+	return fmt.Sprintf("%s element %s (public key %q): %v", e.Op, e.Element, e.PublicKey, e.Err)
+}
+
+func (e *MemberError) Unwrap() error {
+	return e.Err
+}
+
+// Member is an accumulated content together with its element.
+type Member struct {
+	Content AccumulatorContent
+	Element *pbc.Element
+}
+
+// ElementFromContent maps content to its accumulator element, the hash of
+// the content in Zr.
+func ElementFromContent(content Content, pairing *pbc.Pairing) (*pbc.Element, error) {
+	hash, err := content.CalculateHash()
+	if err != nil {
+		return nil, err
+	}
+	return pairing.NewZr().SetFromHash(hash), nil
+}
+
+// Manager maintains an accumulator on behalf of its manager key and keeps
+// the member set indexed by element and by public key, so that duplicate
+// additions and deletions of absent members are refused instead of
+// corrupting the accumulator. Every change is recorded in the history.
+//
+// A Manager is not safe for concurrent use.
+type Manager struct {
+	key         *ManagerKey
+	acc         *Accumulator
+	history     *History
+	byElement   map[string]*Member
+	byPublicKey map[string]*Member
+	dir         string // directory of the log and snapshots, if any
+	log         *OpLog // nil unless the manager was recovered from a directory
+	rotations   []*RotationRecord
+}
+
+// NewManager returns a manager with an empty accumulator at epoch 0.
+func NewManager(key *ManagerKey) *Manager {
+	acc := key.Params.NewAccumulator()
+	return &Manager{
+		key:         key,
+		acc:         acc,
+		history:     NewHistory(acc),
+		byElement:   make(map[string]*Member),
+		byPublicKey: make(map[string]*Member),
+	}
+}
+
+// Close closes the operation log of the manager, if any.
+func (m *Manager) Close() error {
+	if m.log == nil {
+		return nil
+	}
+	return m.log.Close()
+}
+
+// Params returns the public parameters of the accumulator.
+func (m *Manager) Params() *PublicParams {
+	return m.key.Params
+}
+
+// Key returns the manager key.
+func (m *Manager) Key() *ManagerKey {
+	return m.key
+}
+
+// Accumulator returns a copy of the current accumulator.
+func (m *Manager) Accumulator() *Accumulator {
+	return m.acc.Clone()
+}
+
+// History returns the history of the updates made by the manager since the
+// last key rotation.
+func (m *Manager) History() *History {
+	return m.history
+}
+
+// Add accumulates content.
+func (m *Manager) Add(content AccumulatorContent) (*UpdateRecord, error) {
+	return m.Update([]AccumulatorContent{content}, nil)
+}
+
+// Delete removes content from the accumulator.
+func (m *Manager) Delete(content AccumulatorContent) (*UpdateRecord, error) {
+	return m.Update(nil, []AccumulatorContent{content})
+}
+
+// DeletePublicKey removes the member registered with publicKey.
+func (m *Manager) DeletePublicKey(publicKey string) (*UpdateRecord, error) {
+	member, ok := m.byPublicKey[publicKey]
+	if !ok {
+		return nil, &MemberError{Op: OpDelete, PublicKey: publicKey, Err: ErrUnknownMember}
+	}
+	return m.Update(nil, []AccumulatorContent{member.Content})
+}
+
+// Update deletes and adds members in a single epoch and returns the
+// published record. Nothing changes if any deletion targets an absent
+// member or any addition a present one, counting the additions of the same
+// call. A manager with an operation log writes the update to the log
+// before applying it.
+func (m *Manager) Update(added, deleted []AccumulatorContent) (*UpdateRecord, error) {
+	pairing := m.key.Params.Pairing
+	deletedMembers := make([]*Member, len(deleted))
+	deletedElements := make([]*pbc.Element, len(deleted))
+	deleting := make(map[string]bool)
+	for i, content := range deleted {
+		e, member, err := m.lookup(content)
+		if err != nil {
+			return nil, err
+		}
+		id := string(e.Bytes())
+		if member == nil || deleting[id] {
+			return nil, memberError(OpDelete, e, content, ErrUnknownMember)
+		}
+		deleting[id] = true
+		deletedMembers[i] = member
+		deletedElements[i] = e
+	}
+	addedElements := make([]*pbc.Element, len(added))
+	adding := make(map[string]bool)
+	addingKeys := make(map[string]bool)
+	for i, content := range added {
+		e, err := ElementFromContent(content, pairing)
+		if err != nil {
+			return nil, err
+		}
+		id := string(e.Bytes())
+		_, present := m.byElement[id]
+		if (present && !deleting[id]) || adding[id] {
+			return nil, memberError(OpAdd, e, content, ErrDuplicateMember)
+		}
+		if content.PublicKey != "" {
+			owner, taken := m.byPublicKey[content.PublicKey]
+			if (taken && !deleting[string(owner.Element.Bytes())]) || addingKeys[content.PublicKey] {
+				return nil, memberError(OpAdd, e, content, ErrDuplicateMember)
+			}
+			addingKeys[content.PublicKey] = true
+		}
+		adding[id] = true
+		addedElements[i] = e
+	}
+
+	next := m.acc.Clone()
+	rec := next.UpdateWithKey(addedElements, deletedElements, m.key.secret, pairing)
+	if m.log != nil {
+		entry := &LogEntry{Epoch: rec.Epoch, Steps: rec.Steps, Value: rec.Value}
+		entry.Deleted = deletedMembers
+		for i, content := range added {
+			entry.Added = append(entry.Added, &Member{Content: content, Element: addedElements[i]})
+		}
+		if err := m.log.Append(entry); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.history.Append(rec); err != nil {
+		return nil, err
+	}
+	m.acc = next
+	for _, e := range deletedElements {
+		m.unindex(e)
+	}
+	for i, content := range added {
+		m.index(&Member{Content: content, Element: addedElements[i]})
+	}
+	return rec, nil
+}
+
+func memberError(op Op, e *pbc.Element, content AccumulatorContent, err error) *MemberError {
+	return &MemberError{Op: op, Element: hex.EncodeToString(e.Bytes()), PublicKey: content.PublicKey, Err: err}
+}
+
+func (m *Manager) index(member *Member) {
+	m.byElement[string(member.Element.Bytes())] = member
+	if member.Content.PublicKey != "" {
+		m.byPublicKey[member.Content.PublicKey] = member
+	}
+}
+
+func (m *Manager) unindex(e *pbc.Element) {
+	id := string(e.Bytes())
+	member := m.byElement[id]
+	delete(m.byElement, id)
+	if member != nil && member.Content.PublicKey != "" {
+		delete(m.byPublicKey, member.Content.PublicKey)
+	}
+}
+
+// lookup returns the element of content and the member accumulated with
+// exactly that content, or a nil member. The element hashes only the public
+// key and the attributes, run together, so contents that differ in role or
+// in where the public key ends share an element with the member.
+func (m *Manager) lookup(content AccumulatorContent) (*pbc.Element, *Member, error) {
+	e, err := ElementFromContent(content, m.key.Params.Pairing)
+	if err != nil {
+		return nil, nil, err
+	}
+	member, ok := m.byElement[string(e.Bytes())]
+	if !ok || member.Content != content {
+		return e, nil, nil
+	}
+	return e, member, nil
+}
+
+// Contains reports whether content is accumulated, comparing all its
+// fields.
+func (m *Manager) Contains(content AccumulatorContent) bool {
+	_, member, err := m.lookup(content)
+	return err == nil && member != nil
+}
+
+// ContainsElement reports whether element is accumulated.
+func (m *Manager) ContainsElement(element *pbc.Element) bool {
+	_, ok := m.byElement[string(element.Bytes())]
+	return ok
+}
+
+// MemberByPublicKey returns the member registered with publicKey.
+func (m *Manager) MemberByPublicKey(publicKey string) (*Member, bool) {
+	member, ok := m.byPublicKey[publicKey]
+	return member, ok
+}
+
+// Count returns the number of members.
+func (m *Manager) Count() int {
+	return len(m.byElement)
+}
+
+// Members returns all members ordered by element.
+func (m *Manager) Members() []*Member {
+	ids := make([]string, 0, len(m.byElement))
+	for id := range m.byElement {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	members := make([]*Member, len(ids))
+	for i, id := range ids {
+		members[i] = m.byElement[id]
+	}
+	return members
+}
+
+// Range calls fn for every member ordered by element until fn returns
+// false.
+func (m *Manager) Range(fn func(*Member) bool) {
+	for _, member := range m.Members() {
+		if !fn(member) {
+			return
+		}
+	}
+}
+
+// Witness issues a witness for content against the current accumulator.
+func (m *Manager) Witness(content AccumulatorContent) (*Witness, error) {
+	e, member, err := m.lookup(content)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrUnknownMember
+	}
+	return m.acc.EasyWayToGetWitness(e, m.key.secret, m.key.Params.Pairing), nil
+}
+
+// NonMembershipWitness issues a non-membership witness for element against
+// the current accumulator. It returns ErrDuplicateMember if element is
+// accumulated, under any content.
+func (m *Manager) NonMembershipWitness(element *pbc.Element) (*NonMembershipWitness, error) {
+	if m.ContainsElement(element) {
+		return nil, ErrDuplicateMember
+	}
+	pp := m.key.Params
+	return m.acc.NonMembershipWitnessWithKey(element, m.key.secret, pp.G, pp.Pairing), nil
+}
+
+// LinkingCredential issues the linking credential of the member enrolled
+// with publicKey, which must be the PublicKey of a LinkingKey. As the key
+// is part of the member's content, every member has a single credential;
+// it is issued again after a key rotation.
+func (m *Manager) LinkingCredential(publicKey string) (*pbc.Element, error) {
+	member, ok := m.byPublicKey[publicKey]
+	if !ok {
+		return nil, ErrUnknownMember
+	}
+	pp := m.key.Params
+	k, err := pp.decodeLinkingPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return IssueLinkingCredential(member.Element, k, m.key.secret, pp), nil
+}
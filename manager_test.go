@@ -0,0 +1,66 @@
+package accumulator
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestManagerComparesFullContent(t *testing.T) {
+	m := NewManager(setupTypeA(t))
+	alice := AccumulatorContent{PublicKey: "ab", Attributes: "c", Role: "admin"}
+	if _, err := m.Add(alice); err != nil {
+		t.Fatal(err)
+	}
+	// Both share the element of alice.
+	otherRole := AccumulatorContent{PublicKey: "ab", Attributes: "c", Role: "user"}
+	otherSplit := AccumulatorContent{PublicKey: "a", Attributes: "bc", Role: "admin"}
+	for _, content := range []AccumulatorContent{otherRole, otherSplit} {
+		if m.Contains(content) {
+			t.Errorf("Contains(%+v) is true", content)
+		}
+		if _, err := m.Witness(content); !errors.Is(err, ErrUnknownMember) {
+			t.Errorf("Witness(%+v): got %v, want ErrUnknownMember", content, err)
+		}
+		if _, err := m.Delete(content); !errors.Is(err, ErrUnknownMember) {
+			t.Errorf("Delete(%+v): got %v, want ErrUnknownMember", content, err)
+		}
+		if _, err := m.Add(content); !errors.Is(err, ErrDuplicateMember) {
+			t.Errorf("Add(%+v): got %v, want ErrDuplicateMember", content, err)
+		}
+	}
+	if !m.Contains(alice) || m.Count() != 1 {
+		t.Fatal("alice is no longer accumulated")
+	}
+	if _, err := m.Delete(alice); err != nil {
+		t.Fatal(err)
+	}
+	if m.Contains(alice) || m.Count() != 0 {
+		t.Error("alice is still accumulated")
+	}
+}
+
+func TestManagerLogsStoredContent(t *testing.T) {
+	dir := t.TempDir()
+	m, err := Recover(dir, setupTypeA(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := AccumulatorContent{PublicKey: "alice", Attributes: "a", Role: "admin"}
+	if _, err := m.Add(alice); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.DeletePublicKey("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	entries, _, err := ReadLog(filepath.Join(dir, LogFileName), m.Params().Pairing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || len(entries[1].Deleted) != 1 || entries[1].Deleted[0].Content != alice {
+		t.Errorf("log entries do not record the stored content: %+v", entries)
+	}
+}
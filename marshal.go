@@ -0,0 +1,332 @@
+package accumulator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Nik-U/pbc"
+)
+
+var (
+	ErrNoPairing      = errors.New("accumulator: value is not bound to a pairing; decode with PublicParams")
+	ErrInvalidWitness = errors.New("accumulator: witness does not match its accumulator")
+)
+
+// MarshalBinary encodes the accumulator as a version byte, the epoch and the
+// length-prefixed group element.
+func (acc *Accumulator) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.uint64(acc.epoch)
+	e.bytes(acc.value.Bytes())
+	return e.buf, nil
+}
+
+// UnmarshalBinary decodes data into acc. acc must already hold an element of
+// the target pairing, as the accumulators returned by
+// PublicParams.NewAccumulator do.
+func (acc *Accumulator) UnmarshalBinary(data []byte) error {
+	if acc.value == nil {
+		return ErrNoPairing
+	}
+	d := newDecoder(data)
+	epoch := d.uint64()
+	value := d.element(acc.value.Pairing().NewG1())
+	if err := d.finish(); err != nil {
+		return err
+	}
+	if value.Is0() {
+		return ErrInvalidEncoding
+	}
+	acc.value, acc.epoch = value, epoch
+	return nil
+}
+
+type accumulatorJSON struct {
+	Version int    `json:"version"`
+	Epoch   uint64 `json:"epoch"`
+	Value   string `json:"value"`
+}
+
+// MarshalJSON encodes the accumulator with the element in hex.
+func (acc *Accumulator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(accumulatorJSON{
+		Version: encodingVersion,
+		Epoch:   acc.epoch,
+		Value:   hex.EncodeToString(acc.value.Bytes()),
+	})
+}
+
+// UnmarshalJSON is the JSON counterpart of UnmarshalBinary.
+func (acc *Accumulator) UnmarshalJSON(data []byte) error {
+	if acc.value == nil {
+		return ErrNoPairing
+	}
+	var v accumulatorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Version != encodingVersion {
+		return ErrUnsupportedVersion
+	}
+	value := acc.value.Pairing().NewG1()
+	if err := decodeHexElement(value, v.Value); err != nil {
+		return err
+	}
+	if value.Is0() {
+		return ErrInvalidEncoding
+	}
+	acc.value, acc.epoch = value, v.Epoch
+	return nil
+}
+
+// MarshalBinary encodes the witness as a version byte, the epoch of its
+// accumulator, and the length-prefixed witness value, element and
+// accumulator value.
+func (wt *Witness) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.uint64(wt.acc.epoch)
+	e.bytes(wt.value.Bytes())
+	e.bytes(wt.element.Bytes())
+	e.bytes(wt.acc.value.Bytes())
+	return e.buf, nil
+}
+
+// UnmarshalBinary decodes data into wt. wt must already hold elements of the
+// target pairing, as the witnesses returned by PublicParams.NewWitness do.
+func (wt *Witness) UnmarshalBinary(data []byte) error {
+	if wt.value == nil {
+		return ErrNoPairing
+	}
+	pairing := wt.value.Pairing()
+	d := newDecoder(data)
+	epoch := d.uint64()
+	dec := Witness{
+		value:   d.element(pairing.NewG1()),
+		element: d.element(pairing.NewZr()),
+		acc:     Accumulator{value: d.element(pairing.NewG1()), epoch: epoch},
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	if dec.value.Is0() || dec.acc.value.Is0() {
+		return ErrInvalidEncoding
+	}
+	*wt = dec
+	return nil
+}
+
+type witnessJSON struct {
+	Version     int    `json:"version"`
+	Epoch       uint64 `json:"epoch"`
+	Value       string `json:"value"`
+	Element     string `json:"element"`
+	Accumulator string `json:"accumulator"`
+}
+
+// MarshalJSON encodes the witness with the elements in hex.
+func (wt *Witness) MarshalJSON() ([]byte, error) {
+	return json.Marshal(witnessJSON{
+		Version:     encodingVersion,
+		Epoch:       wt.acc.epoch,
+		Value:       hex.EncodeToString(wt.value.Bytes()),
+		Element:     hex.EncodeToString(wt.element.Bytes()),
+		Accumulator: hex.EncodeToString(wt.acc.value.Bytes()),
+	})
+}
+
+// UnmarshalJSON is the JSON counterpart of UnmarshalBinary.
+func (wt *Witness) UnmarshalJSON(data []byte) error {
+	if wt.value == nil {
+		return ErrNoPairing
+	}
+	var v witnessJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Version != encodingVersion {
+		return ErrUnsupportedVersion
+	}
+	pairing := wt.value.Pairing()
+	dec := Witness{
+		value:   pairing.NewG1(),
+		element: pairing.NewZr(),
+		acc:     Accumulator{value: pairing.NewG1(), epoch: v.Epoch},
+	}
+	for _, f := range []struct {
+		el *pbc.Element
+		s  string
+	}{{dec.value, v.Value}, {dec.element, v.Element}, {dec.acc.value, v.Accumulator}} {
+		if err := decodeHexElement(f.el, f.s); err != nil {
+			return err
+		}
+	}
+	if dec.value.Is0() || dec.acc.value.Is0() {
+		return ErrInvalidEncoding
+	}
+	*wt = dec
+	return nil
+}
+
+// NewWitness returns an unset witness bound to the pairing of pp, to decode
+// into with UnmarshalBinary or UnmarshalJSON.
+func (pp *PublicParams) NewWitness() *Witness {
+	return &Witness{
+		value:   pp.Pairing.NewG1(),
+		element: pp.Pairing.NewZr(),
+		acc:     Accumulator{value: pp.Pairing.NewG1()},
+	}
+}
+
+// isJSON reports whether data looks like a JSON object rather than a binary
+// encoding, which always starts with the version byte.
+func isJSON(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// DecodeAccumulator decodes an accumulator in either encoding into the
+// pairing of pp.
+func (pp *PublicParams) DecodeAccumulator(data []byte) (*Accumulator, error) {
+	acc := pp.NewAccumulator()
+	var err error
+	if isJSON(data) {
+		err = acc.UnmarshalJSON(data)
+	} else {
+		err = acc.UnmarshalBinary(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// DecodeWitness decodes a witness in either encoding into the pairing of pp
+// and checks it against the accumulator value it carries.
+func (pp *PublicParams) DecodeWitness(data []byte) (*Witness, error) {
+	wt := pp.NewWitness()
+	var err error
+	if isJSON(data) {
+		err = wt.UnmarshalJSON(data)
+	} else {
+		err = wt.UnmarshalBinary(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !VerifyWitness(wt, &wt.acc, pp.H, pp.PK2, wt.element, pp.Pairing) {
+		return nil, ErrInvalidWitness
+	}
+	return wt, nil
+}
+
+// MarshalBinary encodes the record as a version byte, the epoch, the
+// counted lists of deleted elements, added elements, steps and batch data,
+// and the resulting accumulator value.
+func (rec *UpdateRecord) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.uint64(rec.Epoch)
+	e.elements(rec.Deleted)
+	e.elements(rec.Added)
+	e.elements(rec.Steps)
+	e.elements(rec.Powers)
+	e.bytes(rec.Value.Bytes())
+	return e.buf, nil
+}
+
+type updateRecordJSON struct {
+	Version int      `json:"version"`
+	Epoch   uint64   `json:"epoch"`
+	Deleted []string `json:"deleted,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Steps   []string `json:"steps,omitempty"`
+	Powers  []string `json:"powers,omitempty"`
+	Value   string   `json:"value"`
+}
+
+// MarshalJSON encodes the record with the elements in hex.
+func (rec *UpdateRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(updateRecordJSON{
+		Version: encodingVersion,
+		Epoch:   rec.Epoch,
+		Deleted: encodeHexElements(rec.Deleted),
+		Added:   encodeHexElements(rec.Added),
+		Steps:   encodeHexElements(rec.Steps),
+		Powers:  encodeHexElements(rec.Powers),
+		Value:   hex.EncodeToString(rec.Value.Bytes()),
+	})
+}
+
+// DecodeUpdateRecord decodes a record in either encoding into the pairing
+// of pp.
+func (pp *PublicParams) DecodeUpdateRecord(data []byte) (*UpdateRecord, error) {
+	rec := &UpdateRecord{Value: pp.Pairing.NewG1()}
+	if isJSON(data) {
+		var v updateRecordJSON
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		if v.Version != encodingVersion {
+			return nil, ErrUnsupportedVersion
+		}
+		rec.Epoch = v.Epoch
+		var err error
+		if rec.Deleted, err = decodeHexElements(v.Deleted, pp.Pairing.NewZr); err != nil {
+			return nil, err
+		}
+		if rec.Added, err = decodeHexElements(v.Added, pp.Pairing.NewZr); err != nil {
+			return nil, err
+		}
+		if rec.Steps, err = decodeHexElements(v.Steps, pp.Pairing.NewG1); err != nil {
+			return nil, err
+		}
+		if rec.Powers, err = decodeHexElements(v.Powers, pp.Pairing.NewG1); err != nil {
+			return nil, err
+		}
+		if err := decodeHexElement(rec.Value, v.Value); err != nil {
+			return nil, err
+		}
+	} else {
+		d := newDecoder(data)
+		rec.Epoch = d.uint64()
+		rec.Deleted = d.elements(pp.Pairing.NewZr)
+		rec.Added = d.elements(pp.Pairing.NewZr)
+		rec.Steps = d.elements(pp.Pairing.NewG1)
+		rec.Powers = d.elements(pp.Pairing.NewG1)
+		d.element(rec.Value)
+		if err := d.finish(); err != nil {
+			return nil, err
+		}
+	}
+	if !rec.wellFormed() {
+		return nil, ErrInvalidRecord
+	}
+	return rec, nil
+}
+
+// ReadUpdateRecords decodes the records in r, one JSON record per line.
+// Blank lines are skipped.
+func (pp *PublicParams) ReadUpdateRecords(r io.Reader) ([]*UpdateRecord, error) {
+	br := bufio.NewReader(r)
+	var records []*UpdateRecord
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			rec, derr := pp.DecodeUpdateRecord(data)
+			if derr != nil {
+				return nil, fmt.Errorf("line %d: %w", line, derr)
+			}
+			records = append(records, rec)
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package accumulator
+
+import (
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	acc := pp.NewAccumulator()
+	e := pairing.NewZr().Rand()
+	acc.UpdateWithKey(randElements(pairing, 2), nil, mk.secret, pairing)
+	acc.AddElementWithKey(e, mk.secret, pairing)
+	wit := acc.EasyWayToGetWitness(e, mk.secret, pairing)
+
+	for _, marshal := range []func(interface{}) ([]byte, error){
+		func(v interface{}) ([]byte, error) { return v.(encoding.BinaryMarshaler).MarshalBinary() },
+		json.Marshal,
+	} {
+		data, err := marshal(acc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := pp.DecodeAccumulator(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Equals(acc) {
+			t.Errorf("accumulator changed by the round trip of %q", data)
+		}
+		if data, err = marshal(wit); err != nil {
+			t.Fatal(err)
+		}
+		decodedWit, err := pp.DecodeWitness(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !decodedWit.Value().Equals(wit.Value()) || !decodedWit.Element().Equals(e) || !decodedWit.Accumulator().Equals(acc) {
+			t.Errorf("witness changed by the round trip of %q", data)
+		}
+	}
+}
+
+func TestDecodeRejectsMalformed(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	acc := pp.NewAccumulator()
+	e := pairing.NewZr().Rand()
+	acc.AddElementWithKey(e, mk.secret, pairing)
+	wit := acc.EasyWayToGetWitness(e, mk.secret, pairing)
+	bin, err := acc.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1 := pairing.G1Length()
+	orderTwo := make([]byte, g1)
+
+	// The binary accumulator is the version, the epoch and the value with
+	// its 4-byte length.
+	binary := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrInvalidEncoding},
+		{"other version", append([]byte{2}, bin[1:]...), ErrUnsupportedVersion},
+		{"truncated", bin[:len(bin)-1], ErrInvalidEncoding},
+		{"trailing byte", append(append([]byte(nil), bin...), 0), ErrInvalidEncoding},
+		{"short value", append(append([]byte(nil), bin[:9]...), 0, 0, 0, 1, 7), ErrInvalidEncoding},
+		{"point of order 2", append(append([]byte(nil), bin[:13]...), orderTwo...), ErrNotInSubgroup},
+	}
+	for _, c := range binary {
+		if _, err := pp.DecodeAccumulator(c.data); !errors.Is(err, c.want) {
+			t.Errorf("binary accumulator with %s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+
+	value := hex.EncodeToString(acc.Value().Bytes())
+	identity := hex.EncodeToString(pairing.NewG1().Set0().Bytes())
+	jsonCases := []struct {
+		name, data string
+		want       error
+	}{
+		{"other version", `{"version":2,"epoch":1,"value":"` + value + `"}`, ErrUnsupportedVersion},
+		{"bad hex", `{"version":1,"epoch":1,"value":"zz"}`, ErrInvalidEncoding},
+		{"short value", `{"version":1,"epoch":1,"value":"` + value[2:] + `"}`, ErrInvalidEncoding},
+		{"identity", `{"version":1,"epoch":1,"value":"` + identity + `"}`, ErrInvalidEncoding},
+	}
+	for _, c := range jsonCases {
+		if _, err := pp.DecodeAccumulator([]byte(c.data)); !errors.Is(err, c.want) {
+			t.Errorf("JSON accumulator with %s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+	if err := new(Accumulator).UnmarshalBinary(bin); !errors.Is(err, ErrNoPairing) {
+		t.Errorf("decoding into an accumulator without pairing: got %v, want ErrNoPairing", err)
+	}
+
+	// A witness must verify against the accumulator it carries.
+	data, err := NewWitness(wit.Value(), pairing.NewZr().Rand(), acc).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pp.DecodeWitness(data); !errors.Is(err, ErrInvalidWitness) {
+		t.Errorf("witness for another element: got %v, want ErrInvalidWitness", err)
+	}
+	data, err = wit.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pp.DecodeWitness(data[:len(data)-3]); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("truncated witness: got %v, want ErrInvalidEncoding", err)
+	}
+}
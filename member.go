@@ -0,0 +1,257 @@
+package accumulator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/Nik-U/pbc"
+)
+
+// maxPendingRecords bounds the records a MemberClient holds back while it
+// waits for a missing epoch. The record of the missing epoch itself is
+// always taken.
+const maxPendingRecords = 1 << 16
+
+var ErrElementMismatch = errors.New("accumulator: witness is not for the element of the content")
+
+// MemberClient keeps the witness of one member current. It takes update
+// records in any order, holds back those that arrive early, and applies
+// each record as soon as it is the next one. A record is checked with
+// VerifyRecords against the accumulator it follows before it is applied,
+// and the witness against the new accumulator value after every step. A
+// record that fails is dropped, so that another record for its epoch can
+// take its place; several records may be held back for one epoch until
+// their predecessor tells them apart. Once the member's own element is
+// deleted the client reports ErrWitnessRevoked and stops. After a key
+// rotation the client is moved to the new key with Rotate.
+//
+// A MemberClient is safe for concurrent use.
+type MemberClient struct {
+	content AccumulatorContent
+	element *pbc.Element
+
+	mu      sync.Mutex
+	pp      *PublicParams
+	wit     *Witness
+	pending map[uint64][]*UpdateRecord
+	held    int // records in pending
+	revoked bool
+}
+
+// NewMemberClient returns a client for the member with content and its
+// witness, which must be valid for its accumulator.
+func NewMemberClient(pp *PublicParams, content AccumulatorContent, wit *Witness) (*MemberClient, error) {
+	element, err := ElementFromContent(content, pp.Pairing)
+	if err != nil {
+		return nil, err
+	}
+	if !element.Equals(wit.element) {
+		return nil, ErrElementMismatch
+	}
+	if !VerifyWitness(wit, &wit.acc, pp.H, pp.PK2, element, pp.Pairing) {
+		return nil, ErrInvalidWitness
+	}
+	return &MemberClient{
+		pp:      pp,
+		content: content,
+		element: element,
+		wit:     &Witness{value: wit.value, element: element, acc: wit.acc},
+		pending: make(map[uint64][]*UpdateRecord),
+	}, nil
+}
+
+// Params returns the public parameters the client decodes records with.
+func (mc *MemberClient) Params() *PublicParams {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.pp
+}
+
+// Content returns the content of the member.
+func (mc *MemberClient) Content() AccumulatorContent {
+	return mc.content
+}
+
+// Element returns the element of the member.
+func (mc *MemberClient) Element() *pbc.Element {
+	return mc.element
+}
+
+// Witness returns the current witness.
+func (mc *MemberClient) Witness() *Witness {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	wit := *mc.wit
+	return &wit
+}
+
+// Epoch returns the epoch of the current witness.
+func (mc *MemberClient) Epoch() uint64 {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.wit.acc.epoch
+}
+
+// Pending returns the number of records held back for a missing epoch.
+func (mc *MemberClient) Pending() int {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.held
+}
+
+// Revoked reports whether the member's element was deleted.
+func (mc *MemberClient) Revoked() bool {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.revoked
+}
+
+// Receive takes one update record. Records the witness has already seen
+// are ignored, later ones are kept until the records before them arrive.
+// The record of the next epoch is applied at once, and its error, such as
+// a *TransitionError for a forged record, is returned. An error of a
+// record held back is returned once the record is reached and no other
+// record for its epoch applies. Receive returns ErrWitnessRevoked once the
+// member is deleted. Any other error leaves the witness at the last good
+// epoch.
+func (mc *MemberClient) Receive(rec *UpdateRecord) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.revoked {
+		return ErrWitnessRevoked
+	}
+	if rec.Epoch <= mc.wit.acc.epoch {
+		return nil
+	}
+	if rec.Epoch > mc.wit.acc.epoch+1 {
+		if mc.held >= maxPendingRecords {
+			return ErrMissingUpdate
+		}
+		mc.pending[rec.Epoch] = append(mc.pending[rec.Epoch], rec)
+		mc.held++
+		return nil
+	}
+	if err := mc.apply(rec); err != nil {
+		return err
+	}
+	for {
+		epoch := mc.wit.acc.epoch + 1
+		candidates := mc.pending[epoch]
+		if len(candidates) == 0 {
+			return nil
+		}
+		delete(mc.pending, epoch)
+		mc.held -= len(candidates)
+		var err error
+		for _, next := range candidates {
+			if err = mc.apply(next); err == nil || mc.revoked {
+				break
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Rotate moves the client to the key announced by rot, which is verified
+// against the current parameters, with wit issued under the new key at the
+// epoch of the rotation or later.
+func (mc *MemberClient) Rotate(rot *RotationRecord, wit *Witness) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.revoked {
+		return ErrWitnessRevoked
+	}
+	pp, err := rot.Verify(mc.pp)
+	if err != nil {
+		return err
+	}
+	if !wit.element.Equals(mc.element) {
+		return ErrElementMismatch
+	}
+	if wit.acc.epoch < rot.Epoch || (wit.acc.epoch == rot.Epoch && !wit.acc.value.Equals(rot.Value)) {
+		return ErrInvalidWitness
+	}
+	if !VerifyWitness(wit, &wit.acc, pp.H, pp.PK2, mc.element, pp.Pairing) {
+		return ErrInvalidWitness
+	}
+	mc.pp = pp
+	mc.wit = &Witness{value: wit.value, element: mc.element, acc: wit.acc}
+	for epoch, candidates := range mc.pending {
+		if epoch <= wit.acc.epoch {
+			delete(mc.pending, epoch)
+			mc.held -= len(candidates)
+		}
+	}
+	return nil
+}
+
+// apply verifies rec against the accumulator of the witness, moves the
+// witness over it and verifies the result.
+func (mc *MemberClient) apply(rec *UpdateRecord) error {
+	if err := VerifyRecords(&mc.wit.acc, []*UpdateRecord{rec}, mc.pp.H, mc.pp.PK2, mc.pp.Pairing); err != nil {
+		return err
+	}
+	next := *mc.wit
+	err := next.ApplyUpdates([]*UpdateRecord{rec})
+	if errors.Is(err, ErrWitnessRevoked) {
+		mc.revoked = true
+		mc.pending = make(map[uint64][]*UpdateRecord)
+		mc.held = 0
+	}
+	if err != nil {
+		return err
+	}
+	if !VerifyWitness(&next, &next.acc, mc.pp.H, mc.pp.PK2, mc.element, mc.pp.Pairing) {
+		return ErrInvalidWitness
+	}
+	mc.wit = &next
+	return nil
+}
+
+// Follow receives the records of updates until the channel is closed, ctx
+// is done or Receive fails. It returns nil when the channel is closed.
+func (mc *MemberClient) Follow(ctx context.Context, updates <-chan *UpdateRecord) error {
+	for {
+		select {
+		case rec, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if err := mc.Receive(rec); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ReceiveFrom receives the records in r, as written by the command-line
+// tool: one JSON record per line.
+func (mc *MemberClient) ReceiveFrom(r io.Reader) error {
+	records, err := mc.Params().ReadUpdateRecords(r)
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		if err := mc.Receive(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReceiveFile receives the records in the file at path; see ReceiveFrom.
+func (mc *MemberClient) ReceiveFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return mc.ReceiveFrom(f)
+}
@@ -0,0 +1,177 @@
+package accumulator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Nik-U/pbc"
+)
+
+// newMember enrolls alice among others and returns her client at the
+// current epoch, along with the records of further updates: additions of
+// three members and the deletion of the first of them.
+func newMember(t *testing.T) (*Manager, *MemberClient, []*UpdateRecord) {
+	t.Helper()
+	m := NewManager(setupTypeA(t))
+	alice := AccumulatorContent{PublicKey: "alice"}
+	if _, err := m.Add(alice); err != nil {
+		t.Fatal(err)
+	}
+	wit, err := m.Witness(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc, err := NewMemberClient(m.Params(), alice, wit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []*UpdateRecord
+	for i := 0; i < 3; i++ {
+		rec, err := m.Add(AccumulatorContent{PublicKey: fmt.Sprint("member ", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+	rec, err := m.Delete(AccumulatorContent{PublicKey: "member 0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, mc, append(records, rec)
+}
+
+func checkCurrent(t *testing.T, m *Manager, mc *MemberClient) {
+	t.Helper()
+	pp := m.Params()
+	if mc.Epoch() != m.Accumulator().Epoch() {
+		t.Fatalf("client at epoch %d, want %d", mc.Epoch(), m.Accumulator().Epoch())
+	}
+	if !VerifyWitness(mc.Witness(), m.Accumulator(), pp.H, pp.PK2, mc.Element(), pp.Pairing) {
+		t.Error("witness does not verify against the accumulator")
+	}
+}
+
+// forge returns a copy of rec with a value the manager never published.
+func forge(rec *UpdateRecord, pairing *pbc.Pairing) *UpdateRecord {
+	forged := *rec
+	forged.Value = pairing.NewG1().Rand()
+	forged.Steps = []*pbc.Element{forged.Value}
+	return &forged
+}
+
+func TestMemberClientOutOfOrder(t *testing.T) {
+	m, mc, records := newMember(t)
+	for _, i := range []int{2, 3, 1} {
+		if err := mc.Receive(records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if mc.Epoch() != 1 || mc.Pending() != 3 {
+		t.Fatalf("with a gap: epoch %d with %d pending, want 1 with 3", mc.Epoch(), mc.Pending())
+	}
+	if err := mc.Receive(records[0]); err != nil {
+		t.Fatal(err)
+	}
+	if mc.Pending() != 0 {
+		t.Errorf("%d records still pending", mc.Pending())
+	}
+	checkCurrent(t, m, mc)
+	for _, rec := range records {
+		if err := mc.Receive(rec); err != nil {
+			t.Errorf("record %d received again: %v", rec.Epoch, err)
+		}
+	}
+}
+
+func TestMemberClientRejectsForgedRecord(t *testing.T) {
+	m, mc, records := newMember(t)
+	pairing := m.Params().Pairing
+
+	var terr *TransitionError
+	if err := mc.Receive(forge(records[0], pairing)); !errors.As(err, &terr) {
+		t.Errorf("forged next record: got %v, want a TransitionError", err)
+	}
+	if mc.Epoch() != 1 {
+		t.Fatalf("forged record moved the client to epoch %d", mc.Epoch())
+	}
+
+	// A forged record that arrives early does not shut out the real one.
+	for _, rec := range []*UpdateRecord{forge(records[1], pairing), records[1], records[0]} {
+		if err := mc.Receive(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if mc.Epoch() != 3 {
+		t.Fatalf("client at epoch %d, want 3", mc.Epoch())
+	}
+
+	// Nor does one that failed on its own: a later record replaces it.
+	if err := mc.Receive(forge(records[3], pairing)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.Receive(records[2]); !errors.As(err, &terr) || terr.Epoch != records[3].Epoch {
+		t.Errorf("held forged record: got %v, want a TransitionError at epoch %d", err, records[3].Epoch)
+	}
+	if err := mc.Receive(records[3]); err != nil {
+		t.Fatal(err)
+	}
+	checkCurrent(t, m, mc)
+}
+
+func TestMemberClientRevoked(t *testing.T) {
+	m, mc, records := newMember(t)
+	pairing := m.Params().Pairing
+	for _, rec := range records {
+		if err := mc.Receive(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the manager can revoke alice: a record deleting her element
+	// without the key is refused and leaves her witness in place.
+	forged := &UpdateRecord{Epoch: mc.Epoch() + 1, Deleted: []*pbc.Element{mc.Element()}, Value: pairing.NewG1().Rand()}
+	forged.Steps = []*pbc.Element{forged.Value}
+	if err := mc.Receive(forged); errors.Is(err, ErrWitnessRevoked) || err == nil || mc.Revoked() {
+		t.Fatalf("forged revocation: got %v", err)
+	}
+
+	later, err := m.Add(AccumulatorContent{PublicKey: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := m.Delete(mc.Content())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.Receive(rec); err != nil || mc.Pending() != 1 {
+		t.Fatalf("early revocation: got %v with %d pending", err, mc.Pending())
+	}
+	if err := mc.Receive(later); !errors.Is(err, ErrWitnessRevoked) {
+		t.Errorf("revocation: got %v, want ErrWitnessRevoked", err)
+	}
+	if !mc.Revoked() || mc.Pending() != 0 {
+		t.Errorf("revoked %v with %d pending", mc.Revoked(), mc.Pending())
+	}
+	if err := mc.Receive(later); !errors.Is(err, ErrWitnessRevoked) {
+		t.Errorf("record after the revocation: got %v, want ErrWitnessRevoked", err)
+	}
+}
+
+func TestMemberClientFullBuffer(t *testing.T) {
+	m, mc, records := newMember(t)
+	for i := 0; i < maxPendingRecords; i++ {
+		if err := mc.Receive(&UpdateRecord{Epoch: 100 + uint64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mc.Receive(records[1]); !errors.Is(err, ErrMissingUpdate) {
+		t.Errorf("early record with a full buffer: got %v, want ErrMissingUpdate", err)
+	}
+	for _, rec := range records {
+		if err := mc.Receive(rec); err != nil {
+			t.Fatalf("next record with a full buffer: %v", err)
+		}
+	}
+	checkCurrent(t, m, mc)
+}
@@ -0,0 +1,187 @@
+package accumulator
+
+import (
+	"errors"
+
+	"github.com/Nik-U/pbc"
+)
+
+var ErrElementAdded = errors.New("accumulator: the element of the non-membership witness was added")
+
+// NonMembershipWitness proves that an element is not part of an
+// accumulator. For the accumulator value V and element y it satisfies
+// V = C^(y+key) * g^d with d != 0.
+type NonMembershipWitness struct {
+	c       *pbc.Element // Witness value in G1
+	d       *pbc.Element // Non-zero remainder in Zr
+	element *pbc.Element // Element the witness is for
+	acc     Accumulator  // Accumulator value for current witness
+}
+
+// C returns the group element of the witness.
+// The returned element must not be modified.
+func (wt *NonMembershipWitness) C() *pbc.Element {
+	return wt.c
+}
+
+// D returns the remainder of the witness.
+// The returned element must not be modified.
+func (wt *NonMembershipWitness) D() *pbc.Element {
+	return wt.d
+}
+
+// Element returns the element the witness is for.
+// The returned element must not be modified.
+func (wt *NonMembershipWitness) Element() *pbc.Element {
+	return wt.element
+}
+
+// Epoch returns the epoch of the accumulator the witness matches.
+func (wt *NonMembershipWitness) Epoch() uint64 {
+	return wt.acc.epoch
+}
+
+// Accumulator returns a copy of the accumulator the witness was computed
+// against.
+func (wt *NonMembershipWitness) Accumulator() *Accumulator {
+	return wt.acc.Clone()
+}
+
+// SetAccumulator records a copy of acc as the accumulator the witness
+// matches.
+func (wt *NonMembershipWitness) SetAccumulator(acc *Accumulator) {
+	wt.acc = *acc.Clone()
+}
+
+// Get a non-membership witness with the help of the manager key.
+// The caller must make sure that u_priv is not part of the accumulator: the
+// key allows a witness for any element. Manager.NonMembershipWitness checks
+// this against its members.
+func (acc *Accumulator) NonMembershipWitnessWithKey(u_priv, key, g *pbc.Element, pairing *pbc.Pairing) *NonMembershipWitness {
+	d := randNonZero(pairing)
+	index := pairing.NewZr().Add(u_priv, key)
+	index2 := pairing.NewZr().Invert(index)
+	c := pairing.NewG1().Sub(acc.value, pairing.NewG1().PowZn(g, d))
+	wt := &NonMembershipWitness{
+		c:       c.PowZn(c, index2),
+		d:       d,
+		element: pairing.NewZr().Set(u_priv),
+	}
+	wt.SetAccumulator(acc)
+	return wt
+}
+
+// Update a non-membership witness (Based on old accumulator)
+// wt: witness
+// e_add: new element
+// e_self: self element
+func (wt *NonMembershipWitness) AddElementForNonMembership(e_add, e_self *pbc.Element, pairing *pbc.Pairing) *NonMembershipWitness {
+	index := pairing.NewZr().Sub(e_add, e_self)
+	c := pairing.NewG1().PowZn(wt.c, index)
+	wt.c = c.Add(c, wt.acc.value)
+	wt.d = pairing.NewZr().Mul(wt.d, index)
+	return wt
+}
+
+// Update a non-membership witness (Based on new accumulator)
+// wt: witness
+// e_delete: element to be deleted
+// e_self: self element
+// acc: new accumulator
+func (wt *NonMembershipWitness) DeleteElementForNonMembership(e_delete, e_self *pbc.Element, acc *Accumulator, pairing *pbc.Pairing) *NonMembershipWitness {
+	index := pairing.NewZr().Sub(e_delete, e_self)
+	index2 := pairing.NewZr().Invert(index)
+	c := pairing.NewG1().Sub(wt.c, acc.value)
+	wt.c = c.PowZn(c, index2)
+	wt.d = pairing.NewZr().Mul(wt.d, index2)
+	return wt
+}
+
+// ApplyUpdates is the non-membership counterpart of Witness.ApplyUpdates.
+// It fails with ErrElementAdded once the element joins the accumulator.
+func (wt *NonMembershipWitness) ApplyUpdates(records []*UpdateRecord) error {
+	pairing := wt.c.Pairing()
+	next := &NonMembershipWitness{c: wt.c, d: wt.d, element: wt.element, acc: wt.acc}
+	for _, rec := range records {
+		if rec.Epoch <= next.acc.epoch {
+			continue
+		}
+		if rec.Epoch != next.acc.epoch+1 {
+			return ErrMissingUpdate
+		}
+		for _, e := range rec.Deleted {
+			if e.Equals(next.element) {
+				return ErrInvalidRecord
+			}
+		}
+		for _, e := range rec.Added {
+			if e.Equals(next.element) {
+				return ErrElementAdded
+			}
+		}
+		if elements, op, ok := rec.batch(); ok {
+			if err := next.applyBatch(rec, elements, op, pairing); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(rec.Steps) != len(rec.Deleted)+len(rec.Added) {
+			return ErrCompactRecord
+		}
+		for i, e := range rec.Deleted {
+			next.DeleteElementForNonMembership(e, next.element, &Accumulator{value: rec.Steps[i]}, pairing)
+			next.acc.value = rec.Steps[i]
+		}
+		for i, e := range rec.Added {
+			next.AddElementForNonMembership(e, next.element, pairing)
+			next.acc.value = rec.Steps[len(rec.Deleted)+i]
+		}
+		if !next.acc.value.Equals(rec.Value) {
+			return ErrInvalidRecord
+		}
+		next.acc.epoch = rec.Epoch
+	}
+	*wt = *next
+	return nil
+}
+
+// applyBatch applies the batch record rec like Witness.applyBatch. For
+// V = C^(y+key) * g^d it is
+//
+//	add:    C' = B^Q(key) * C^P(-y),         d' = d * P(-y)
+//	delete: C' = (C / B^Q(key))^(1/P(-y)),   d' = d / P(-y)
+func (wt *NonMembershipWitness) applyBatch(rec *UpdateRecord, elements []*pbc.Element, op Op, pairing *pbc.Pairing) error {
+	base := rec.Value
+	if op == OpAdd {
+		base = wt.acc.value
+	}
+	if !rec.Powers[0].Equals(base) {
+		return ErrInvalidRecord
+	}
+	q, rem := divideBatch(batchPolynomial(elements, pairing), wt.element, pairing)
+	if rem.Is0() {
+		return ErrInvalidRecord
+	}
+	bq := powerProduct(rec.Powers, q, pairing)
+	if op == OpAdd {
+		wt.c = pairing.NewG1().Add(bq, pairing.NewG1().PowZn(wt.c, rem))
+		wt.d = pairing.NewZr().Mul(wt.d, rem)
+	} else {
+		inv := pairing.NewZr().Invert(rem)
+		c := pairing.NewG1().Sub(wt.c, bq)
+		wt.c = c.PowZn(c, inv)
+		wt.d = pairing.NewZr().Mul(wt.d, inv)
+	}
+	wt.acc = Accumulator{value: rec.Value, epoch: rec.Epoch}
+	return nil
+}
+
+// e(C, h^e * h^p) = e(Acc / g^d, h) and d != 0
+func VerifyNonMembership(wit *NonMembershipWitness, acc *Accumulator, g, h, pk2, u_priv *pbc.Element, pairing *pbc.Pairing) bool {
+	if wit.d.Is0() {
+		return false
+	}
+	temp1 := pairing.NewGT().Pair(wit.c, pairing.NewG2().Add(pk2, pairing.NewG2().PowZn(h, u_priv)))
+	temp2 := pairing.NewGT().Pair(pairing.NewG1().Sub(acc.value, pairing.NewG1().PowZn(g, wit.d)), h)
+	return temp1.Equals(temp2)
+}
@@ -0,0 +1,66 @@
+package accumulator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNonMembership(t *testing.T) {
+	m := NewManager(setupTypeA(t))
+	pp := m.Params()
+	pairing := pp.Pairing
+	alice := AccumulatorContent{PublicKey: "alice"}
+	carol := AccumulatorContent{PublicKey: "carol"}
+	if _, err := m.Add(alice); err != nil {
+		t.Fatal(err)
+	}
+	member, err := ElementFromContent(alice, pairing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.NonMembershipWitness(member); !errors.Is(err, ErrDuplicateMember) {
+		t.Errorf("witness for a member: got %v, want ErrDuplicateMember", err)
+	}
+	outsider, err := ElementFromContent(carol, pairing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wit, err := m.NonMembershipWitness(outsider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyNonMembership(wit, m.Accumulator(), pp.G, pp.H, pp.PK2, outsider, pairing) {
+		t.Fatal("non-membership witness does not verify")
+	}
+	if VerifyNonMembership(wit, m.Accumulator(), pp.G, pp.H, pp.PK2, member, pairing) {
+		t.Error("non-membership witness verifies for a member")
+	}
+
+	add, err := m.Add(AccumulatorContent{PublicKey: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	del, err := m.Delete(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range []*UpdateRecord{add, del} {
+		if err := wit.ApplyUpdates([]*UpdateRecord{rec}); err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyNonMembership(wit, NewAccumulatorAt(rec.Value, rec.Epoch), pp.G, pp.H, pp.PK2, outsider, pairing) {
+			t.Errorf("non-membership witness does not verify after epoch %d", rec.Epoch)
+		}
+	}
+
+	rec, err := m.Add(carol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wit.ApplyUpdates([]*UpdateRecord{rec}); !errors.Is(err, ErrElementAdded) {
+		t.Errorf("record adding the element: got %v, want ErrElementAdded", err)
+	}
+	if _, err := m.NonMembershipWitness(outsider); !errors.Is(err, ErrDuplicateMember) {
+		t.Errorf("witness for a new member: got %v, want ErrDuplicateMember", err)
+	}
+}
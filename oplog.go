@@ -0,0 +1,374 @@
+package accumulator
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+
+	"github.com/Nik-U/pbc"
+)
+
+// LogFileName is the name of the operation log in a manager directory.
+const LogFileName = "manager.log"
+
+var (
+	ErrCorruptLog  = errors.New("accumulator: operation log is corrupt")
+	ErrLogMismatch = errors.New("accumulator: replayed value differs from the logged value")
+	ErrLogUnusable = errors.New("accumulator: operation log is unusable after a failed write")
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// frameHeaderSize is the size of the length and the two checksums in front
+// of every payload.
+const frameHeaderSize = 12
+
+// LogEntry is one update of a Manager as written to its operation log.
+//
+// On disk the log is a sequence of frames
+//
+//	length   uint32, length of the payload
+//	header   uint32, CRC-32C (Castagnoli) of the length
+//	checksum uint32, CRC-32C of the payload
+//	payload
+//
+// with integers in big-endian order. The payload is the version byte
+// followed by
+//
+//	epoch    uint64
+//	deleted  uint64 count, then per member the length-prefixed element,
+//	         public key, attributes and role
+//	added    the same as deleted
+//	steps    uint64 count, then the length-prefixed accumulator value after
+//	         each deletion and addition
+//	value    length-prefixed accumulator value after the update
+//
+// The entry holds everything the published update record does, so the
+// history can be read back without the manager key, see LoadHistory.
+//
+// A frame that is cut short or fails its checksum at the end of the file is
+// the trace of a crash during the write and is discarded on recovery. The
+// length has a checksum of its own, so that a damaged length cannot make a
+// frame in the middle of the log look cut short.
+type LogEntry struct {
+	Epoch   uint64
+	Deleted []*Member
+	Added   []*Member
+	Steps   []*pbc.Element
+	Value   *pbc.Element
+}
+
+// MarshalBinary encodes the payload of the entry.
+func (entry *LogEntry) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.uint64(entry.Epoch)
+	e.members(entry.Deleted)
+	e.members(entry.Added)
+	e.elements(entry.Steps)
+	e.bytes(entry.Value.Bytes())
+	return e.buf, nil
+}
+
+// Record returns the update record published for the entry.
+func (entry *LogEntry) Record() *UpdateRecord {
+	rec := &UpdateRecord{Epoch: entry.Epoch, Steps: entry.Steps, Value: entry.Value}
+	for _, m := range entry.Deleted {
+		rec.Deleted = append(rec.Deleted, m.Element)
+	}
+	for _, m := range entry.Added {
+		rec.Added = append(rec.Added, m.Element)
+	}
+	return rec
+}
+
+func decodeLogEntry(data []byte, pairing *pbc.Pairing) (*LogEntry, error) {
+	d := newDecoder(data)
+	entry := &LogEntry{Epoch: d.uint64()}
+	entry.Deleted = d.members(pairing)
+	entry.Added = d.members(pairing)
+	entry.Steps = d.elements(pairing.NewG1)
+	entry.Value = d.element(pairing.NewG1())
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// OpLog appends entries to an operation log file. Every entry is synced to
+// disk before Append returns.
+type OpLog struct {
+	f    *os.File
+	size int64
+	err  error // set once a failed write could not be cut back
+}
+
+// OpenLog opens the log at path for appending, creating it if needed.
+func OpenLog(path string) (*OpLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &OpLog{f: f, size: info.Size()}, nil
+}
+
+// Append writes entry as one frame and syncs the file. If the write fails,
+// the file is cut back to its previous length so that later entries do not
+// follow a partial frame. If that fails too, the log is unusable: this and
+// every later Append return ErrLogUnusable, and the partial frame is left
+// for Recover to drop.
+func (l *OpLog) Append(entry *LogEntry) error {
+	if l.err != nil {
+		return l.err
+	}
+	frame, err := encodeFrame(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.f.Write(frame); err != nil {
+		return l.cutBack(err)
+	}
+	if err := l.f.Sync(); err != nil {
+		return l.cutBack(err)
+	}
+	l.size += int64(len(frame))
+	return nil
+}
+
+// cutBack truncates the file to the end of the last good frame after the
+// write error err.
+func (l *OpLog) cutBack(err error) error {
+	if terr := l.f.Truncate(l.size); terr != nil {
+		l.err = fmt.Errorf("%w: %v; cutting it back: %v", ErrLogUnusable, err, terr)
+		return l.err
+	}
+	return err
+}
+
+// encodeFrame returns entry with its length and checksums in front.
+func encodeFrame(entry *LogEntry) ([]byte, error) {
+	payload, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.Checksum(frame[0:4], crcTable))
+	binary.BigEndian.PutUint32(frame[8:12], crc32.Checksum(payload, crcTable))
+	return append(frame, payload...), nil
+}
+
+// Close closes the log file.
+func (l *OpLog) Close() error {
+	return l.f.Close()
+}
+
+// ReadLog reads all entries of the log at path. A damaged frame at the end
+// of the file is dropped; valid is the length of the file up to the last
+// good frame. A frame is taken for the end of the file only if its header
+// is cut short, if it is zeros up to the end of the file, as a file system
+// may leave after a crash while the file grew, or if its header is intact
+// and its payload is cut short or fails its checksum with nothing following
+// it. Any other damage is reported as ErrCorruptLog.
+func ReadLog(path string, pairing *pbc.Pairing) (entries []*LogEntry, valid int64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	for len(data) > 0 {
+		if len(data) < frameHeaderSize || allZero(data) {
+			break
+		}
+		if crc32.Checksum(data[0:4], crcTable) != binary.BigEndian.Uint32(data[4:8]) {
+			return nil, 0, fmt.Errorf("%w: bad frame header at offset %d", ErrCorruptLog, valid)
+		}
+		n := uint64(binary.BigEndian.Uint32(data[0:4]))
+		if uint64(len(data)-frameHeaderSize) < n {
+			break
+		}
+		end := frameHeaderSize + int(n)
+		payload := data[frameHeaderSize:end]
+		if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(data[8:12]) {
+			if len(data) == end {
+				break
+			}
+			return nil, 0, fmt.Errorf("%w: bad checksum at offset %d", ErrCorruptLog, valid)
+		}
+		entry, err := decodeLogEntry(payload, pairing)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: offset %d: %v", ErrCorruptLog, valid, err)
+		}
+		entries = append(entries, entry)
+		valid += int64(end)
+		data = data[end:]
+	}
+	return entries, valid, nil
+}
+
+func allZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Recover rebuilds the manager kept in dir. It starts from the newest
+// readable snapshot, or from an empty accumulator if there is none, and
+// replays the newer entries of the operation log with key. Every replayed
+// update must reproduce the logged accumulator value. A partial last entry
+// left by a crash is removed from the log. The returned manager logs its
+// further updates to the same file; dir and the log are created if they do
+// not exist. The rotation records in dir that lead to key are loaded, and
+// those of rotations that did not complete are removed.
+//
+// The value restored from a snapshot is not recomputed; call Verify to
+// check it against the member set.
+func Recover(dir string, key *ManagerKey) (*Manager, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, LogFileName)
+	entries, valid, err := ReadLog(path, key.Params.Pairing)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := os.Truncate(path, valid); err != nil {
+			return nil, err
+		}
+	}
+	m, err := restoreNewestSnapshot(dir, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.replay(entries); err != nil {
+		return nil, err
+	}
+	if err := m.loadRotations(dir); err != nil {
+		return nil, err
+	}
+	m.dir = dir
+	if m.log, err = OpenLog(path); err != nil {
+		return nil, err
+	}
+	if err := syncDir(dir); err != nil {
+		m.log.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// replay applies the logged entries newer than the state of m, checking
+// each against the log.
+func (m *Manager) replay(entries []*LogEntry) error {
+	for _, entry := range entries {
+		if entry.Epoch <= m.acc.epoch {
+			continue
+		}
+		if entry.Epoch != m.acc.epoch+1 {
+			return fmt.Errorf("%w: entry for epoch %d follows epoch %d", ErrCorruptLog, entry.Epoch, m.acc.epoch)
+		}
+		deleted, err := m.loggedContents(entry.Deleted)
+		if err != nil {
+			return err
+		}
+		added, err := m.loggedContents(entry.Added)
+		if err != nil {
+			return err
+		}
+		rec, err := m.Update(added, deleted)
+		if err != nil {
+			return fmt.Errorf("epoch %d: %w", entry.Epoch, err)
+		}
+		if !rec.Value.Equals(entry.Value) || !equalElements(rec.Steps, entry.Steps) {
+			return fmt.Errorf("%w: epoch %d", ErrLogMismatch, entry.Epoch)
+		}
+	}
+	return nil
+}
+
+func equalElements(a, b []*pbc.Element) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadHistory reads the history of the manager kept in dir without the
+// manager key, for members and auditors who can read the directory. Like
+// Recover it starts at the newest readable snapshot and takes the records
+// from the log entries after it, but it does not check them with the key
+// and leaves a partial last entry in place.
+func LoadHistory(dir string, pp *PublicParams) (*History, error) {
+	start := pp.NewAccumulator()
+	epochs, err := snapshotEpochs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, epoch := range epochs {
+		s, err := ReadSnapshot(filepath.Join(dir, snapshotName(epoch)), pp)
+		if errors.Is(err, ErrKeyMismatch) {
+			return nil, err
+		}
+		if err == nil {
+			start = &Accumulator{value: s.Value, epoch: s.Epoch}
+			break
+		}
+	}
+	entries, _, err := ReadLog(filepath.Join(dir, LogFileName), pp.Pairing)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	history := NewHistory(start)
+	for _, entry := range entries {
+		if entry.Epoch <= start.epoch {
+			continue
+		}
+		if err := history.Append(entry.Record()); err != nil {
+			return nil, fmt.Errorf("%w: entry for epoch %d follows epoch %d", ErrCorruptLog, entry.Epoch, history.LatestEpoch())
+		}
+	}
+	return history, nil
+}
+
+// loggedContents returns the contents of logged members after checking
+// that each one still maps to its logged element.
+func (m *Manager) loggedContents(members []*Member) ([]AccumulatorContent, error) {
+	contents := make([]AccumulatorContent, len(members))
+	for i, member := range members {
+		e, err := ElementFromContent(member.Content, m.key.Params.Pairing)
+		if err != nil {
+			return nil, err
+		}
+		if !e.Equals(member.Element) {
+			return nil, fmt.Errorf("%w: element of %q", ErrLogMismatch, member.Content.PublicKey)
+		}
+		contents[i] = member.Content
+	}
+	return contents, nil
+}
+
+// syncDir makes the creation of files in dir durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
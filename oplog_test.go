@@ -0,0 +1,211 @@
+package accumulator
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeLog recovers a manager in a new directory, adds n members and
+// returns the directory, the key and the offsets at which the frames of the
+// log start.
+func writeLog(t *testing.T, n int) (string, *ManagerKey, []int64) {
+	t.Helper()
+	dir := t.TempDir()
+	mk := setupTypeA(t)
+	m, err := Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var offsets []int64
+	for i := 0; i < n; i++ {
+		offsets = append(offsets, m.log.size)
+		if _, err := m.Add(AccumulatorContent{PublicKey: fmt.Sprint("member ", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return dir, mk, offsets
+}
+
+func TestReadLogTornTail(t *testing.T) {
+	dir, mk, offsets := writeLog(t, 3)
+	path := filepath.Join(dir, LogFileName)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range []int64{info.Size() - 1, offsets[2] + frameHeaderSize + 3, offsets[2] + 5} {
+		if err := os.Truncate(path, size); err != nil {
+			t.Fatal(err)
+		}
+		entries, valid, err := ReadLog(path, mk.Params.Pairing)
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if len(entries) != 2 || valid != offsets[2] {
+			t.Errorf("size %d: got %d entries up to %d, want 2 up to %d", size, len(entries), valid, offsets[2])
+		}
+	}
+	m, err := Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if m.Count() != 2 {
+		t.Errorf("recovered %d members, want 2", m.Count())
+	}
+}
+
+func TestReadLogZeroTail(t *testing.T) {
+	dir, mk, offsets := writeLog(t, 3)
+	path := filepath.Join(dir, LogFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The file grew by the last frame, which never reached the disk.
+	for i := offsets[2]; i < int64(len(data)); i++ {
+		data[i] = 0
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	entries, valid, err := ReadLog(path, mk.Params.Pairing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || valid != offsets[2] {
+		t.Errorf("got %d entries up to %d, want 2 up to %d", len(entries), valid, offsets[2])
+	}
+
+	// Zeros with data after them are not a torn tail.
+	data[len(data)-1] = 1
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadLog(path, mk.Params.Pairing); !errors.Is(err, ErrCorruptLog) {
+		t.Errorf("zero header before data: got %v, want ErrCorruptLog", err)
+	}
+}
+
+func TestAppendAfterFailedCutBack(t *testing.T) {
+	dir, mk, _ := writeLog(t, 1)
+	path := filepath.Join(dir, LogFileName)
+	entries, _, err := ReadLog(path, mk.Params.Pairing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := OpenLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Writing to and truncating a closed file both fail.
+	l.f.Close()
+	if err := l.Append(entries[0]); !errors.Is(err, ErrLogUnusable) {
+		t.Fatalf("failed write and cut back: got %v, want ErrLogUnusable", err)
+	}
+	if l.f, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if err := l.Append(entries[0]); !errors.Is(err, ErrLogUnusable) {
+		t.Errorf("append after a failed cut back: got %v, want ErrLogUnusable", err)
+	}
+}
+
+func TestReadLogCorruptLength(t *testing.T) {
+	dir, mk, offsets := writeLog(t, 3)
+	path := filepath.Join(dir, LogFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The length of the middle frame now points past the end of the file.
+	binary.BigEndian.PutUint32(data[offsets[1]:], uint32(len(data)))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadLog(path, mk.Params.Pairing); !errors.Is(err, ErrCorruptLog) {
+		t.Errorf("ReadLog: got %v, want ErrCorruptLog", err)
+	}
+	if _, err := Recover(dir, mk); !errors.Is(err, ErrCorruptLog) {
+		t.Errorf("Recover: got %v, want ErrCorruptLog", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(len(data)) {
+		t.Errorf("log was cut to %d bytes", info.Size())
+	}
+}
+
+func TestReadLogCorruptPayload(t *testing.T) {
+	dir, mk, offsets := writeLog(t, 3)
+	path := filepath.Join(dir, LogFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[offsets[1]+frameHeaderSize+1] ^= 1
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadLog(path, mk.Params.Pairing); !errors.Is(err, ErrCorruptLog) {
+		t.Errorf("ReadLog: got %v, want ErrCorruptLog", err)
+	}
+}
+
+func TestLoadHistoryWithoutKey(t *testing.T) {
+	dir := t.TempDir()
+	mk := setupTypeA(t)
+	m, err := Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	alice := AccumulatorContent{PublicKey: "alice"}
+	if _, err := m.Add(alice); err != nil {
+		t.Fatal(err)
+	}
+	wit, err := m.Witness(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := m.Add(AccumulatorContent{PublicKey: fmt.Sprint("member ", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := m.DeletePublicKey("member 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := LoadHistory(dir, mk.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.FirstEpoch() != wit.Epoch() || !history.Latest().Equals(m.Accumulator()) {
+		t.Fatalf("history covers epochs %d to %d, want %d to %d", history.FirstEpoch(), history.LatestEpoch(), wit.Epoch(), m.Accumulator().Epoch())
+	}
+	records, err := history.RecordsSince(wit.Epoch())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wit.ApplyUpdates(records); err != nil {
+		t.Fatal(err)
+	}
+	pp := mk.Params
+	if !VerifyWitness(wit, m.Accumulator(), pp.H, pp.PK2, wit.Element(), pp.Pairing) {
+		t.Error("witness invalid after the updates of the loaded history")
+	}
+}
@@ -0,0 +1,186 @@
+package accumulator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Nik-U/pbc"
+)
+
+var ErrInvalidParams = errors.New("accumulator: public parameters are inconsistent")
+
+// PublicParams bundles everything a verifier needs to check witnesses: the
+// pairing, the generators g and h and the manager public keys. On
+// asymmetric curves G1 and G2 differ: accumulator values and witnesses are
+// in G1, h and pk2 in G2.
+type PublicParams struct {
+	Params  string       // pairing parameters in the pbc text format
+	Pairing *pbc.Pairing // pairing built from Params
+	G       *pbc.Element // generator of G1
+	H       *pbc.Element // generator of G2
+	PK1     *pbc.Element // g^key, the value of the empty accumulator
+	PK2     *pbc.Element // h^key
+}
+
+// Setup generates Type A pairing parameters, random generators and a fresh
+// manager key. The key is returned separately and must be kept secret. Use
+// SetupCurve for other curves and sizes.
+func Setup(rbits, qbits uint32) (*PublicParams, *pbc.Element) {
+	return SetupParams(pbc.GenerateA(rbits, qbits))
+}
+
+// NewAccumulator returns the empty accumulator of the system.
+func (pp *PublicParams) NewAccumulator() *Accumulator {
+	return NewAccumulator(pp.PK1)
+}
+
+// Validate checks that pk1 and pk2 were computed from the same key,
+// e(pk1, h) = e(g, pk2).
+func (pp *PublicParams) Validate() error {
+	if pp.G.Is0() || pp.H.Is0() {
+		return ErrInvalidParams
+	}
+	temp1 := pp.Pairing.NewGT().Pair(pp.PK1, pp.H)
+	temp2 := pp.Pairing.NewGT().Pair(pp.G, pp.PK2)
+	if !temp1.Equals(temp2) {
+		return ErrInvalidParams
+	}
+	return nil
+}
+
+// MarshalBinary encodes the parameters as a version byte followed by the
+// length-prefixed parameter string, g, h, pk1 and pk2.
+func (pp *PublicParams) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.bytes([]byte(pp.Params))
+	e.bytes(pp.G.Bytes())
+	e.bytes(pp.H.Bytes())
+	e.bytes(pp.PK1.Bytes())
+	e.bytes(pp.PK2.Bytes())
+	return e.buf, nil
+}
+
+// UnmarshalBinary rebuilds the pairing and the elements from data and
+// validates the result.
+func (pp *PublicParams) UnmarshalBinary(data []byte) error {
+	d := newDecoder(data)
+	params := d.bytes()
+	if d.err != nil {
+		return d.err
+	}
+	pairing, err := pbc.NewPairingFromString(string(params))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	dec := PublicParams{
+		Params:  string(params),
+		Pairing: pairing,
+		G:       d.element(pairing.NewG1()),
+		H:       d.element(pairing.NewG2()),
+		PK1:     d.element(pairing.NewG1()),
+		PK2:     d.element(pairing.NewG2()),
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	if err := dec.Validate(); err != nil {
+		return err
+	}
+	*pp = dec
+	return nil
+}
+
+type publicParamsJSON struct {
+	Version int    `json:"version"`
+	Params  string `json:"params"`
+	G       string `json:"g"`
+	H       string `json:"h"`
+	PK1     string `json:"pk1"`
+	PK2     string `json:"pk2"`
+}
+
+// MarshalJSON encodes the parameters with the elements in hex.
+func (pp *PublicParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(publicParamsJSON{
+		Version: encodingVersion,
+		Params:  pp.Params,
+		G:       hex.EncodeToString(pp.G.Bytes()),
+		H:       hex.EncodeToString(pp.H.Bytes()),
+		PK1:     hex.EncodeToString(pp.PK1.Bytes()),
+		PK2:     hex.EncodeToString(pp.PK2.Bytes()),
+	})
+}
+
+// UnmarshalJSON is the JSON counterpart of UnmarshalBinary.
+func (pp *PublicParams) UnmarshalJSON(data []byte) error {
+	var v publicParamsJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Version != encodingVersion {
+		return ErrUnsupportedVersion
+	}
+	pairing, err := pbc.NewPairingFromString(v.Params)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	dec := PublicParams{
+		Params:  v.Params,
+		Pairing: pairing,
+		G:       pairing.NewG1(),
+		H:       pairing.NewG2(),
+		PK1:     pairing.NewG1(),
+		PK2:     pairing.NewG2(),
+	}
+	for _, f := range []struct {
+		el *pbc.Element
+		s  string
+	}{{dec.G, v.G}, {dec.H, v.H}, {dec.PK1, v.PK1}, {dec.PK2, v.PK2}} {
+		if err := decodeHexElement(f.el, f.s); err != nil {
+			return err
+		}
+	}
+	if err := dec.Validate(); err != nil {
+		return err
+	}
+	*pp = dec
+	return nil
+}
+
+// LoadPublicParams decodes parameters in either the binary or the JSON
+// encoding.
+func LoadPublicParams(data []byte) (*PublicParams, error) {
+	pp := new(PublicParams)
+	var err error
+	if isJSON(data) {
+		err = pp.UnmarshalJSON(data)
+	} else {
+		err = pp.UnmarshalBinary(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pp, nil
+}
+
+// ReadPublicParams loads parameters from a file written with
+// WritePublicParams or any other encoding accepted by LoadPublicParams.
+func ReadPublicParams(path string) (*PublicParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadPublicParams(data)
+}
+
+// WritePublicParams stores the JSON encoding of pp in path.
+func WritePublicParams(path string, pp *PublicParams) error {
+	data, err := pp.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,162 @@
+package accumulator
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Nik-U/pbc"
+)
+
+var (
+	ErrUnknownParamSet = errors.New("accumulator: unknown parameter set")
+	ErrParamsChecksum  = errors.New("accumulator: pairing parameters do not match their checksum")
+)
+
+// paramSetFiles holds the named pairing parameters shipped with the package.
+//
+// a-80 is a.param of the pbc distribution. a-112 and a-128 are the output
+// of pbc.GenerateA(224, 1024) and pbc.GenerateA(256, 1536), which draws its
+// primes at random, so the files themselves are the record; what can be
+// checked is their form, which TestParamSetForm does: r = 2^exp2 +
+// sign1*2^exp1 + sign0 and q = h*r - 1 are prime and h is a multiple of 12.
+//
+// f-128 is the Barreto-Naehrig curve of the Type F preset for 128 bits, made
+// without randomness so that it can be rebuilt: x is the first integer from
+// 2^114 up for which q = 36x^4 - 36x^3 + 24x^2 - 6x + 1 (or the same with +x)
+// and r = q + 1 - (6x^2 + 1) are prime, which TestParamSetForm repeats; b is
+// the smallest for which y^2 = x^3 + b has r points, beta the smallest
+// non-residue and alpha = alpha0 + alpha1*sqrt(beta), taken in order of
+// alpha1 then alpha0, the first for which x^6 + alpha is irreducible over
+// Fq2 and the twist y^2 = x^3 - alpha*b has a subgroup of order r.
+//
+//go:embed paramsets/*.param
+var paramSetFiles embed.FS
+
+// paramSets maps the name of each shipped parameter set to the SHA-256
+// hash of its text.
+var paramSets = map[string]string{
+	"a-80":  "23427c7c855a78c2600dbc6477896d90eb0106aaf5743527e52e5f8667cdfa42",
+	"a-112": "1f887092a83eeae3d460208cacb7013f8af5ed068a1d692e83d0d59e327e62e6",
+	"a-128": "8eea1568af5fed642cc304d77955edcd4c2e4a3740665449e58cf75cf3b64bba",
+	"f-128": "fbe6588a255d614d07aa4acdf01ce84e86d8bfdfa43e57d55dfea29845443a7c",
+}
+
+// ParamSetNames returns the names of the shipped parameter sets.
+func ParamSetNames() []string {
+	names := make([]string, 0, len(paramSets))
+	for name := range paramSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadParamSet returns the shipped pairing parameters called name after
+// checking them against their checksum.
+func LoadParamSet(name string) (*pbc.Params, error) {
+	sum, ok := paramSets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownParamSet, name)
+	}
+	data, err := paramSetFiles.ReadFile("paramsets/" + name + ".param")
+	if err != nil {
+		return nil, err
+	}
+	return parseCheckedParams(string(data), sum)
+}
+
+// SetupNamed is Setup on a shipped parameter set, so that all systems set
+// up with the same name share the pairing.
+func SetupNamed(name string) (*PublicParams, *pbc.Element, error) {
+	params, err := LoadParamSet(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	pp, key := SetupParams(params)
+	return pp, key, nil
+}
+
+// SetupManagerKeyNamed runs SetupNamed and wraps the generated key.
+func SetupManagerKeyNamed(name string) (*ManagerKey, error) {
+	params, err := LoadParamSet(name)
+	if err != nil {
+		return nil, err
+	}
+	return SetupManagerKeyParams(params), nil
+}
+
+func parseCheckedParams(text, sum string) (*pbc.Params, error) {
+	if digest := sha256.Sum256([]byte(text)); hex.EncodeToString(digest[:]) != sum {
+		return nil, ErrParamsChecksum
+	}
+	return pbc.NewParamsFromString(text)
+}
+
+// cachedParamsJSON is the format of the files written by GenerateCached.
+type cachedParamsJSON struct {
+	Version int    `json:"version"`
+	Curve   Curve  `json:"curve"`
+	Params  string `json:"params"`
+	SHA256  string `json:"sha256"`
+}
+
+// DefaultParamsCacheDir returns the directory GenerateCached uses when
+// given none, below the user cache directory.
+func DefaultParamsCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "accumulator", "params"), nil
+}
+
+// GenerateCached returns the parameters generated for c earlier and cached
+// in dir, or generates and caches them. dir defaults to
+// DefaultParamsCacheDir. A cache file that fails its checksum or was made
+// for another curve is replaced.
+func (c Curve) GenerateCached(dir string) (*pbc.Params, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultParamsCacheDir(); err != nil {
+			return nil, err
+		}
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d-%d-%d-%d.json", c.Type, c.RBits, c.QBits, c.D, c.BitLimit))
+	if data, err := os.ReadFile(path); err == nil {
+		var v cachedParamsJSON
+		if json.Unmarshal(data, &v) == nil && v.Version == encodingVersion && v.Curve == c {
+			if params, err := parseCheckedParams(v.Params, v.SHA256); err == nil {
+				return params, nil
+			}
+		}
+	}
+	params, err := c.Generate()
+	if err != nil {
+		return nil, err
+	}
+	text := params.String()
+	digest := sha256.Sum256([]byte(text))
+	data, err := json.Marshal(cachedParamsJSON{
+		Version: encodingVersion,
+		Curve:   c,
+		Params:  text,
+		SHA256:  hex.EncodeToString(digest[:]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
@@ -0,0 +1,126 @@
+package accumulator
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParamSetForm(t *testing.T) {
+	for _, name := range ParamSetNames() {
+		data, err := paramSetFiles.ReadFile("paramsets/" + name + ".param")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadParamSet(name); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var typ string
+		fields := make(map[string]*big.Int)
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			kv := strings.Fields(line)
+			if len(kv) != 2 {
+				continue
+			}
+			if kv[0] == "type" {
+				typ = kv[1]
+				continue
+			}
+			n, ok := new(big.Int).SetString(kv[1], 10)
+			if !ok {
+				t.Fatalf("%s: %s is not a number", name, kv[0])
+			}
+			fields[kv[0]] = n
+		}
+		switch typ {
+		case "a":
+			checkTypeAForm(t, name, fields)
+		case "f":
+			checkTypeFForm(t, name, fields)
+		default:
+			t.Errorf("%s: unexpected type %q", name, typ)
+		}
+	}
+}
+
+func checkTypeAForm(t *testing.T, name string, fields map[string]*big.Int) {
+	t.Helper()
+	q, h, r := fields["q"], fields["h"], fields["r"]
+	exp2, exp1, sign1, sign0 := fields["exp2"], fields["exp1"], fields["sign1"], fields["sign0"]
+	if q == nil || h == nil || r == nil || exp2 == nil || exp1 == nil || sign1 == nil || sign0 == nil {
+		t.Fatalf("%s: missing fields", name)
+	}
+
+	want := new(big.Int).Lsh(big.NewInt(1), uint(exp2.Uint64()))
+	want.Add(want, new(big.Int).Mul(sign1, new(big.Int).Lsh(big.NewInt(1), uint(exp1.Uint64()))))
+	want.Add(want, sign0)
+	if r.Cmp(want) != 0 {
+		t.Errorf("%s: r is not 2^exp2 + sign1*2^exp1 + sign0", name)
+	}
+	if want.Mul(h, r).Sub(want, big.NewInt(1)); q.Cmp(want) != 0 {
+		t.Errorf("%s: q is not h*r - 1", name)
+	}
+	if new(big.Int).Mod(h, big.NewInt(12)).Sign() != 0 {
+		t.Errorf("%s: h is not a multiple of 12", name)
+	}
+	if !r.ProbablyPrime(32) || !q.ProbablyPrime(32) {
+		t.Errorf("%s: r or q is not prime", name)
+	}
+}
+
+// checkTypeFForm repeats the search for x described at paramSetFiles and
+// checks that it ends at q and r.
+func checkTypeFForm(t *testing.T, name string, fields map[string]*big.Int) {
+	t.Helper()
+	q, r, beta := fields["q"], fields["r"], fields["beta"]
+	if q == nil || r == nil || beta == nil || fields["b"] == nil || fields["alpha0"] == nil || fields["alpha1"] == nil {
+		t.Fatalf("%s: missing fields", name)
+	}
+	bn := func(x *big.Int, sign int64) (*big.Int, *big.Int) {
+		// q = 36x^4 + sign*36x^3 + 24x^2 + sign*6x + 1, r = q - 6x^2.
+		x2 := new(big.Int).Mul(x, x)
+		q := new(big.Int).Mul(x2, x2)
+		q.Mul(q, big.NewInt(36))
+		q.Add(q, new(big.Int).Mul(new(big.Int).Mul(x2, x), big.NewInt(36*sign)))
+		q.Add(q, new(big.Int).Mul(x2, big.NewInt(24)))
+		q.Add(q, new(big.Int).Mul(x, big.NewInt(6*sign)))
+		q.Add(q, big.NewInt(1))
+		return q, new(big.Int).Sub(q, x2.Mul(x2, big.NewInt(6)))
+	}
+	xbits := uint(r.BitLen()-6) / 4
+search:
+	for x := new(big.Int).Lsh(big.NewInt(1), xbits); ; x.Add(x, big.NewInt(1)) {
+		for _, sign := range []int64{-1, 1} {
+			if cq, cr := bn(x, sign); cq.ProbablyPrime(20) && cr.ProbablyPrime(20) {
+				if cq.Cmp(q) != 0 || cr.Cmp(r) != 0 {
+					t.Errorf("%s: q and r are not the first found from 2^%d", name, xbits)
+				}
+				break search
+			}
+		}
+	}
+	if big.Jacobi(beta, q) != -1 {
+		t.Errorf("%s: beta is a square", name)
+	}
+}
+
+func TestParamSetPairing(t *testing.T) {
+	for _, name := range ParamSetNames() {
+		params, err := LoadParamSet(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		pairing := params.NewPairing()
+		g, h := pairing.NewG1().Rand(), pairing.NewG2().Rand()
+		a, b := pairing.NewZr().Rand(), pairing.NewZr().Rand()
+		left := pairing.NewGT().Pair(pairing.NewG1().PowZn(g, a), pairing.NewG2().PowZn(h, b))
+		right := pairing.NewGT().Pair(g, h)
+		right.PowZn(right, pairing.NewZr().Mul(a, b))
+		if !left.Equals(right) {
+			t.Errorf("%s: e(g^a, h^b) != e(g, h)^ab", name)
+		}
+		if pairing.NewGT().Pair(g, h).Is1() {
+			t.Errorf("%s: the pairing is degenerate", name)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package accumulator
+
+import (
+	"errors"
+
+	"github.com/Nik-U/pbc"
+)
+
+var ErrNoCommitment = errors.New("accumulator: Respond called without a pending commitment")
+
+// The membership proof is a Sigma protocol for knowledge of an element e and
+// a witness W with e(W, pk2 * h^e) = e(Acc, h) that reveals neither.
+//
+// The prover picks a random r and sends the randomized witness WBar = W^r
+// together with VBar = Acc^r / WBar^e, which equals WBar^key. Anybody can
+// check VBar = WBar^key with a pairing, so it remains to prove knowledge of
+// (r, e) with VBar = Acc^r / WBar^e, a Schnorr proof over G1.
+
+// MembershipCommitment is the first message of the membership proof.
+type MembershipCommitment struct {
+	WBar *pbc.Element // randomized witness W^r
+	VBar *pbc.Element // Acc^r / WBar^e = WBar^key
+	T    *pbc.Element // Acc^rho1 / WBar^rho2
+}
+
+// MembershipResponse is the answer of the prover to the challenge.
+type MembershipResponse struct {
+	S1 *pbc.Element // rho1 + c * r
+	S2 *pbc.Element // rho2 + c * e
+}
+
+// MembershipProver holds the secret state of one run of the membership
+// proof.
+type MembershipProver struct {
+	wit     *Witness
+	pairing *pbc.Pairing
+
+	r, rho1, rho2 *pbc.Element
+}
+
+// NewMembershipProver prepares a proof that the element of wit is part of
+// the accumulator wit was computed against.
+func NewMembershipProver(wit *Witness, pairing *pbc.Pairing) *MembershipProver {
+	return &MembershipProver{wit: wit, pairing: pairing}
+}
+
+// Commit starts a new run of the protocol with fresh randomness.
+func (p *MembershipProver) Commit() *MembershipCommitment {
+	p.r = randNonZero(p.pairing)
+	p.rho1 = p.pairing.NewZr().Rand()
+	p.rho2 = p.pairing.NewZr().Rand()
+	return commitMembership(p.wit, p.r, p.rho1, p.rho2, p.pairing)
+}
+
+// Respond answers challenge for the last commitment. The randomness is
+// discarded afterwards: answering two challenges for the same commitment
+// would reveal the element.
+func (p *MembershipProver) Respond(challenge *pbc.Element) (*MembershipResponse, error) {
+	if p.r == nil {
+		return nil, ErrNoCommitment
+	}
+	resp := respondMembership(p.wit, p.r, p.rho1, p.rho2, challenge, p.pairing)
+	p.r, p.rho1, p.rho2 = nil, nil, nil
+	return resp, nil
+}
+
+// NewChallenge returns a random challenge for the verifier to send.
+func NewChallenge(pairing *pbc.Pairing) *pbc.Element {
+	return pairing.NewZr().Rand()
+}
+
+func commitMembership(wit *Witness, r, rho1, rho2 *pbc.Element, pairing *pbc.Pairing) *MembershipCommitment {
+	wBar := pairing.NewG1().PowZn(wit.value, r)
+	return &MembershipCommitment{
+		WBar: wBar,
+		VBar: pairing.NewG1().Sub(pairing.NewG1().PowZn(wit.acc.value, r), pairing.NewG1().PowZn(wBar, wit.element)),
+		T:    pairing.NewG1().Sub(pairing.NewG1().PowZn(wit.acc.value, rho1), pairing.NewG1().PowZn(wBar, rho2)),
+	}
+}
+
+func respondMembership(wit *Witness, r, rho1, rho2, challenge *pbc.Element, pairing *pbc.Pairing) *MembershipResponse {
+	return &MembershipResponse{
+		S1: pairing.NewZr().Add(rho1, pairing.NewZr().Mul(challenge, r)),
+		S2: pairing.NewZr().Add(rho2, pairing.NewZr().Mul(challenge, wit.element)),
+	}
+}
+
+// e(WBar, pk2) = e(VBar, h) and Acc^s1 / WBar^s2 = T * VBar^c
+func VerifyMembershipProof(com *MembershipCommitment, challenge *pbc.Element, resp *MembershipResponse, acc *Accumulator, h, pk2 *pbc.Element, pairing *pbc.Pairing) bool {
+	if !inSubgroup(com.T) || !checkRandomizedWitness(com.WBar, com.VBar, h, pk2, pairing) {
+		return false
+	}
+	lhs := pairing.NewG1().Sub(pairing.NewG1().PowZn(acc.value, resp.S1), pairing.NewG1().PowZn(com.WBar, resp.S2))
+	rhs := pairing.NewG1().Add(com.T, pairing.NewG1().PowZn(com.VBar, challenge))
+	return lhs.Equals(rhs)
+}
+
+// checkRandomizedWitness checks that wBar is not the identity, that both
+// elements lie in the subgroup of order r and that vBar = wBar^key,
+// e(wBar, pk2) = e(vBar, h). Without the subgroup check a point of small
+// order passes the pairing check for any vBar of the same order, and the
+// Schnorr equation can be solved without a witness.
+func checkRandomizedWitness(wBar, vBar, h, pk2 *pbc.Element, pairing *pbc.Pairing) bool {
+	if wBar.Is0() || !inSubgroup(wBar) || !inSubgroup(vBar) {
+		return false
+	}
+	temp1 := pairing.NewGT().Pair(wBar, pk2)
+	temp2 := pairing.NewGT().Pair(vBar, h)
+	return temp1.Equals(temp2)
+}
+
+// randNonZero returns a random non-zero element of Zr.
+func randNonZero(pairing *pbc.Pairing) *pbc.Element {
+	x := pairing.NewZr().Rand()
+	for x.Is0() {
+		x.Rand()
+	}
+	return x
+}
@@ -0,0 +1,139 @@
+package accumulator
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Nik-U/pbc"
+)
+
+// setupTypeA returns a manager key on the shipped Type A parameters, whose
+// curve has the point (0, 0) of order 2.
+func setupTypeA(t *testing.T) *ManagerKey {
+	t.Helper()
+	mk, err := SetupManagerKeyNamed("a-80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mk
+}
+
+// orderTwoPoint returns the point (0, 0), which pbc decodes from zero bytes
+// since it lies on the curve.
+func orderTwoPoint(pairing *pbc.Pairing) *pbc.Element {
+	return pairing.NewG1().SetBytes(make([]byte, pairing.G1Length()))
+}
+
+// forgeMemberSignature solves the Schnorr equation of a member signature
+// with WBar = VBar = (0, 0), which needs no witness: T is guessed for one
+// parity of the challenge until the hash agrees.
+func forgeMemberSignature(msg []byte, acc *Accumulator, pairing *pbc.Pairing) *MemberSignature {
+	p := orderTwoPoint(pairing)
+	for {
+		s1 := pairing.NewZr().Rand()
+		s2 := pairing.NewZr().Rand()
+		// T = Acc^s1 / WBar^s2 / VBar^c = Acc^s1 * P^(s2+c) for c even.
+		t := pairing.NewG1().Add(pairing.NewG1().PowZn(acc.value, s1), pairing.NewG1().PowZn(p, s2))
+		com := &MembershipCommitment{WBar: p, VBar: p, T: t}
+		c := memberChallenge(msg, acc.value, com, pairing)
+		if c.BigInt().Bit(0) == 0 {
+			return &MemberSignature{WBar: p, VBar: p, C: c, S1: s1, S2: s2}
+		}
+	}
+}
+
+func TestOrderTwoPointRejected(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	acc := pp.NewAccumulator()
+	acc.AddElementWithKey(pairing.NewZr().Rand(), mk.secret, pairing)
+	msg := []byte("message")
+
+	p := orderTwoPoint(pairing)
+	if p.Is0() || !p.NewFieldElement().Add(p, p).Is0() {
+		t.Fatal("zero bytes do not decode to a point of order 2")
+	}
+	sig := forgeMemberSignature(msg, acc, pairing)
+	if !pairing.NewGT().Pair(sig.WBar, pp.PK2).Equals(pairing.NewGT().Pair(sig.VBar, pp.H)) {
+		t.Fatal("point of order 2 does not pass the pairing check")
+	}
+	if VerifyMemberSignature(msg, sig, acc, pp) {
+		t.Error("forged member signature accepted")
+	}
+
+	data, err := sig.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pp.DecodeMemberSignature(data); !errors.Is(err, ErrNotInSubgroup) {
+		t.Errorf("DecodeMemberSignature: got %v, want ErrNotInSubgroup", err)
+	}
+	if data, err = json.Marshal(sig); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pp.DecodeMemberSignature(data); !errors.Is(err, ErrNotInSubgroup) {
+		t.Errorf("DecodeMemberSignature of JSON: got %v, want ErrNotInSubgroup", err)
+	}
+
+	linkable := &LinkableSignature{MemberSignature: *sig, SigmaBar: p, SBar: p, S3: sig.S1, S4: sig.S2, Tag: pairing.NewGT().Pair(acc.value, pp.H)}
+	if data, err = linkable.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pp.DecodeLinkableSignature(data); !errors.Is(err, ErrNotInSubgroup) {
+		t.Errorf("DecodeLinkableSignature: got %v, want ErrNotInSubgroup", err)
+	}
+	if VerifyLinkableSignature(msg, []byte("context"), linkable, acc, pp) {
+		t.Error("linkable signature with a point of order 2 accepted")
+	}
+
+	com := &MembershipCommitment{WBar: p, VBar: p, T: sig.WBar}
+	resp := &MembershipResponse{S1: sig.S1, S2: sig.S2}
+	if VerifyMembershipProof(com, sig.C, resp, acc, pp.H, pp.PK2, pairing) {
+		t.Error("membership proof with a point of order 2 accepted")
+	}
+
+	wit := acc.EasyWayToGetWitness(pairing.NewZr().Rand(), mk.secret, pairing)
+	wit.value.Add(wit.value, p)
+	if data, err = wit.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pp.DecodeWitness(data); !errors.Is(err, ErrNotInSubgroup) {
+		t.Errorf("DecodeWitness: got %v, want ErrNotInSubgroup", err)
+	}
+}
+
+func TestSignaturesStillVerify(t *testing.T) {
+	m := NewManager(setupTypeA(t))
+	pp := m.Params()
+	cred := linkingMember(t, m, "user")
+	acc := m.Accumulator()
+	wit, err := m.Witness(AccumulatorContent{PublicKey: cred.key.PublicKey(), Role: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := cred.element
+
+	data, err := SignAsMember([]byte("message"), wit, e).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := pp.DecodeMemberSignature(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyMemberSignature([]byte("message"), sig, acc, pp) {
+		t.Error("member signature rejected")
+	}
+	linkable := SignLinkable([]byte("message"), []byte("context"), wit, cred, pp)
+	if data, err = linkable.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	if linkable, err = pp.DecodeLinkableSignature(data); err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyLinkableSignature([]byte("message"), []byte("context"), linkable, acc, pp) {
+		t.Error("linkable signature rejected")
+	}
+}
@@ -0,0 +1,354 @@
+package accumulator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Nik-U/pbc"
+)
+
+// rotationDomain separates the hashes signed by rotation records from other
+// uses of the hash.
+const rotationDomain = "accumulator/rotation/v1"
+
+const (
+	rotationPrefix = "rotation-"
+	rotationSuffix = ".json"
+)
+
+var ErrInvalidRotation = errors.New("accumulator: rotation record is invalid")
+
+// RotationRecord announces the replacement of the manager key. The
+// accumulator is rebuilt over the same members under the new key at Epoch,
+// the epoch after Previous. The record is signed with BLS signatures under
+// both keys, so it links the old pk2 to the new one: whoever trusts the old
+// parameters can derive the new ones with Verify.
+//
+// Witnesses and update records do not carry over a rotation; every member
+// needs a witness issued under the new key.
+type RotationRecord struct {
+	Epoch        uint64       // first epoch under the new key
+	Previous     *pbc.Element // last accumulator value under the old key
+	OldPK1       *pbc.Element
+	OldPK2       *pbc.Element
+	NewPK1       *pbc.Element
+	NewPK2       *pbc.Element
+	Value        *pbc.Element // accumulator value at Epoch
+	OldSignature *pbc.Element // H(record)^old key in G1
+	NewSignature *pbc.Element // H(record)^new key in G1
+}
+
+// Next returns a fresh manager key over the pairing and generators of mk.
+func (mk *ManagerKey) Next() *ManagerKey {
+	pp := mk.Params
+	secret := randNonZero(pp.Pairing)
+	return &ManagerKey{
+		Params: &PublicParams{
+			Params:  pp.Params,
+			Pairing: pp.Pairing,
+			G:       pp.G,
+			H:       pp.H,
+			PK1:     pp.Pairing.NewG1().PowZn(pp.G, secret),
+			PK2:     pp.Pairing.NewG2().PowZn(pp.H, secret),
+		},
+		secret: secret,
+	}
+}
+
+// digest hashes the signed fields of the record into G1.
+func (rot *RotationRecord) digest(pairing *pbc.Pairing) *pbc.Element {
+	h := sha256.New()
+	var epoch [8]byte
+	binary.BigEndian.PutUint64(epoch[:], rot.Epoch)
+	for _, part := range [][]byte{[]byte(rotationDomain), epoch[:], rot.Previous.Bytes(),
+		rot.OldPK1.Bytes(), rot.OldPK2.Bytes(), rot.NewPK1.Bytes(), rot.NewPK2.Bytes(), rot.Value.Bytes()} {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(part)))
+		h.Write(n[:])
+		h.Write(part)
+	}
+	return pairing.NewG1().SetFromHash(h.Sum(nil))
+}
+
+// Verify checks the record against the parameters in force before it and
+// returns the parameters after it.
+func (rot *RotationRecord) Verify(old *PublicParams) (*PublicParams, error) {
+	pairing := old.Pairing
+	if !rot.OldPK1.Equals(old.PK1) || !rot.OldPK2.Equals(old.PK2) {
+		return nil, ErrKeyMismatch
+	}
+	next := &PublicParams{
+		Params:  old.Params,
+		Pairing: pairing,
+		G:       old.G,
+		H:       old.H,
+		PK1:     pairing.NewG1().Set(rot.NewPK1),
+		PK2:     pairing.NewG2().Set(rot.NewPK2),
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	digest := rot.digest(pairing)
+	// e(H(m)^key, h) = e(H(m), h^key)
+	for _, check := range []struct{ sig, pk2 *pbc.Element }{{rot.OldSignature, old.PK2}, {rot.NewSignature, next.PK2}} {
+		if !pairing.NewGT().Pair(check.sig, old.H).Equals(pairing.NewGT().Pair(digest, check.pk2)) {
+			return nil, ErrInvalidRotation
+		}
+	}
+	return next, nil
+}
+
+// Rotate replaces the manager key with next, which must share the pairing
+// and generators of the current key (see ManagerKey.Next). The accumulator
+// is rebuilt over the current members at the next epoch and the signed
+// rotation record is returned; members get their new witnesses from
+// RangeWitnesses or Witness.
+//
+// The history so far is sealed, so its subscribers are told that it ended,
+// and a new history starts at the rotation. A manager with a directory
+// stores the record and a snapshot under the new key before switching, and
+// drops its older snapshots and log entries; next must be saved before
+// Rotate is called, as Recover needs it afterwards.
+func (m *Manager) Rotate(next *ManagerKey) (*RotationRecord, error) {
+	old := m.key.Params
+	pp := next.Params
+	if pp.Params != old.Params || !pp.G.Equals(old.G) || !pp.H.Equals(old.H) {
+		return nil, ErrKeyMismatch
+	}
+	if pp.PK2.Equals(old.PK2) {
+		return nil, ErrInvalidRotation
+	}
+	pairing := pp.Pairing
+	elements := make([]*pbc.Element, 0, len(m.byElement))
+	for _, member := range m.byElement {
+		elements = append(elements, member.Element)
+	}
+	acc := &Accumulator{
+		value: pairing.NewG1().PowZn(pp.PK1, productWithKey(elements, next.secret, pairing)),
+		epoch: m.acc.epoch + 1,
+	}
+	rot := &RotationRecord{
+		Epoch:    acc.epoch,
+		Previous: m.acc.Value(),
+		OldPK1:   old.PK1,
+		OldPK2:   old.PK2,
+		NewPK1:   pp.PK1,
+		NewPK2:   pp.PK2,
+		Value:    acc.Value(),
+	}
+	digest := rot.digest(pairing)
+	rot.OldSignature = pairing.NewG1().PowZn(digest, m.key.secret)
+	rot.NewSignature = pairing.NewG1().PowZn(digest, next.secret)
+
+	if m.dir != "" {
+		if err := m.persistRotation(next, acc, rot); err != nil {
+			return nil, err
+		}
+	}
+	m.history.Seal()
+	m.history = NewHistory(acc)
+	m.key = next
+	m.acc = acc
+	m.rotations = append(m.rotations, rot)
+	return rot, nil
+}
+
+// persistRotation writes the rotation record and a snapshot of the rotated
+// state, then removes the older snapshots and empties the log. A crash
+// before the snapshot is in place leaves the old state, whose stray record
+// is dropped by Recover.
+func (m *Manager) persistRotation(next *ManagerKey, acc *Accumulator, rot *RotationRecord) error {
+	data, err := json.Marshal(rot)
+	if err != nil {
+		return err
+	}
+	recordPath := filepath.Join(m.dir, rotationName(rot.Epoch))
+	if err := writeFileAtomic(recordPath, data, 0644); err != nil {
+		return err
+	}
+	s := &Snapshot{Params: next.Params, Epoch: acc.epoch, Value: acc.value, Members: m.Members()}
+	if data, err = s.MarshalBinary(); err == nil {
+		err = writeFileAtomic(filepath.Join(m.dir, snapshotName(acc.epoch)), data, 0600)
+	}
+	if err != nil {
+		os.Remove(recordPath)
+		return err
+	}
+	epochs, err := snapshotEpochs(m.dir)
+	if err != nil {
+		return err
+	}
+	for _, epoch := range epochs {
+		if epoch < acc.epoch {
+			if err := os.Remove(filepath.Join(m.dir, snapshotName(epoch))); err != nil {
+				return err
+			}
+		}
+	}
+	return m.compactLog(acc.epoch)
+}
+
+// Rotations returns the rotation records of the manager, oldest first.
+func (m *Manager) Rotations() []*RotationRecord {
+	return append([]*RotationRecord(nil), m.rotations...)
+}
+
+// RangeWitnesses issues a witness against the current accumulator for every
+// member ordered by element and calls fn with it until fn returns false.
+func (m *Manager) RangeWitnesses(fn func(*Member, *Witness) bool) {
+	pairing := m.key.Params.Pairing
+	for _, member := range m.Members() {
+		if !fn(member, m.acc.EasyWayToGetWitness(member.Element, m.key.secret, pairing)) {
+			return
+		}
+	}
+}
+
+// loadRotations reads the rotation records in dir that lead to the key of
+// m, and removes the others, which were left by rotations that did not
+// complete.
+func (m *Manager) loadRotations(dir string) error {
+	names, err := filepath.Glob(filepath.Join(dir, rotationPrefix+"*"+rotationSuffix))
+	if err != nil {
+		return err
+	}
+	type stored struct {
+		path string
+		rot  *RotationRecord
+	}
+	var rotations []stored
+	for _, name := range names {
+		s := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), rotationPrefix), rotationSuffix)
+		epoch, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		rot, err := m.key.Params.DecodeRotationRecord(data)
+		if err == nil && rot.Epoch != epoch {
+			err = ErrInvalidRotation
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		rotations = append(rotations, stored{name, rot})
+	}
+	sort.Slice(rotations, func(i, j int) bool { return rotations[i].rot.Epoch > rotations[j].rot.Epoch })
+	pk2 := m.key.Params.PK2
+	m.rotations = nil
+	for _, r := range rotations {
+		if r.rot.Epoch > m.acc.epoch || !r.rot.NewPK2.Equals(pk2) {
+			if err := os.Remove(r.path); err != nil {
+				return err
+			}
+			continue
+		}
+		m.rotations = append([]*RotationRecord{r.rot}, m.rotations...)
+		pk2 = r.rot.OldPK2
+	}
+	return nil
+}
+
+func rotationName(epoch uint64) string {
+	return fmt.Sprintf("%s%020d%s", rotationPrefix, epoch, rotationSuffix)
+}
+
+// MarshalBinary encodes the record as a version byte followed by the
+// epoch and the length-prefixed previous value, old pk1 and pk2, new pk1
+// and pk2, value and the two signatures.
+func (rot *RotationRecord) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	e.uint64(rot.Epoch)
+	for _, el := range rot.fields() {
+		e.bytes(el.Bytes())
+	}
+	return e.buf, nil
+}
+
+func (rot *RotationRecord) fields() []*pbc.Element {
+	return []*pbc.Element{rot.Previous, rot.OldPK1, rot.OldPK2, rot.NewPK1, rot.NewPK2,
+		rot.Value, rot.OldSignature, rot.NewSignature}
+}
+
+type rotationRecordJSON struct {
+	Version      int    `json:"version"`
+	Epoch        uint64 `json:"epoch"`
+	Previous     string `json:"previous"`
+	OldPK1       string `json:"old_pk1"`
+	OldPK2       string `json:"old_pk2"`
+	NewPK1       string `json:"new_pk1"`
+	NewPK2       string `json:"new_pk2"`
+	Value        string `json:"value"`
+	OldSignature string `json:"old_signature"`
+	NewSignature string `json:"new_signature"`
+}
+
+// MarshalJSON encodes the record with the elements in hex.
+func (rot *RotationRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rotationRecordJSON{
+		Version:      encodingVersion,
+		Epoch:        rot.Epoch,
+		Previous:     hex.EncodeToString(rot.Previous.Bytes()),
+		OldPK1:       hex.EncodeToString(rot.OldPK1.Bytes()),
+		OldPK2:       hex.EncodeToString(rot.OldPK2.Bytes()),
+		NewPK1:       hex.EncodeToString(rot.NewPK1.Bytes()),
+		NewPK2:       hex.EncodeToString(rot.NewPK2.Bytes()),
+		Value:        hex.EncodeToString(rot.Value.Bytes()),
+		OldSignature: hex.EncodeToString(rot.OldSignature.Bytes()),
+		NewSignature: hex.EncodeToString(rot.NewSignature.Bytes()),
+	})
+}
+
+// DecodeRotationRecord decodes a rotation record in either encoding into
+// the pairing of pp. It does not verify the signatures.
+func (pp *PublicParams) DecodeRotationRecord(data []byte) (*RotationRecord, error) {
+	pairing := pp.Pairing
+	rot := &RotationRecord{
+		Previous:     pairing.NewG1(),
+		OldPK1:       pairing.NewG1(),
+		OldPK2:       pairing.NewG2(),
+		NewPK1:       pairing.NewG1(),
+		NewPK2:       pairing.NewG2(),
+		Value:        pairing.NewG1(),
+		OldSignature: pairing.NewG1(),
+		NewSignature: pairing.NewG1(),
+	}
+	if isJSON(data) {
+		var v rotationRecordJSON
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		if v.Version != encodingVersion {
+			return nil, ErrUnsupportedVersion
+		}
+		rot.Epoch = v.Epoch
+		hexes := []string{v.Previous, v.OldPK1, v.OldPK2, v.NewPK1, v.NewPK2, v.Value, v.OldSignature, v.NewSignature}
+		for i, el := range rot.fields() {
+			if err := decodeHexElement(el, hexes[i]); err != nil {
+				return nil, err
+			}
+		}
+		return rot, nil
+	}
+	d := newDecoder(data)
+	rot.Epoch = d.uint64()
+	for _, el := range rot.fields() {
+		d.element(el)
+	}
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	return rot, nil
+}
@@ -0,0 +1,162 @@
+package accumulator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recoverWithMembers recovers a manager in dir and enrolls n members.
+func recoverWithMembers(t *testing.T, dir string, mk *ManagerKey, n int) *Manager {
+	t.Helper()
+	m, err := Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if _, err := m.Add(AccumulatorContent{PublicKey: fmt.Sprint("member ", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+// copyDir copies the files of the directory src into a new directory.
+func copyDir(t *testing.T, src string) string {
+	t.Helper()
+	dst := t.TempDir()
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dst
+}
+
+func TestRotationRecovery(t *testing.T) {
+	dir := t.TempDir()
+	mk := setupTypeA(t)
+	m := recoverWithMembers(t, dir, mk, 3)
+	if err := m.WriteSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	before := copyDir(t, dir)
+
+	m, err := Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := mk.Next()
+	rot, err := m.Rotate(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The process dies before the new key takes the place of the old one:
+	// the directory is only readable with the pending next key.
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Recover(dir, mk); !errors.Is(err, ErrKeyMismatch) {
+		t.Fatalf("recover with the old key after the rotation: got %v, want ErrKeyMismatch", err)
+	}
+	m, err = Recover(dir, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotations := m.Rotations(); len(rotations) != 1 || rotations[0].Epoch != rot.Epoch {
+		t.Fatalf("rotations after recovery: %v", rotations)
+	}
+	if acc := m.Accumulator(); acc.Epoch() != rot.Epoch || !acc.Value().Equals(rot.Value) || m.Count() != 3 {
+		t.Errorf("recovered accumulator at epoch %d with %d members, want the rotated one", acc.Epoch(), m.Count())
+	}
+	if _, err := m.Add(AccumulatorContent{PublicKey: "after"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The process dies after writing the record but before the snapshot
+	// under the new key: the record is stray and the old key stays.
+	stray := rotationName(rot.Epoch)
+	data, err := os.ReadFile(filepath.Join(dir, stray))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(before, stray), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Recover(before, next); !errors.Is(err, ErrKeyMismatch) {
+		t.Errorf("recover with the next key before the rotation: got %v, want ErrKeyMismatch", err)
+	}
+	m, err = Recover(before, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if len(m.Rotations()) != 0 || m.Accumulator().Epoch() != rot.Epoch-1 {
+		t.Errorf("stray record kept: %d rotations at epoch %d", len(m.Rotations()), m.Accumulator().Epoch())
+	}
+	if _, err := os.Stat(filepath.Join(before, stray)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("stray record not removed: %v", err)
+	}
+}
+
+func TestRotationRecordVerify(t *testing.T) {
+	mk := setupTypeA(t)
+	m := recoverWithMembers(t, t.TempDir(), mk, 2)
+	defer m.Close()
+	first, err := m.Rotate(mk.Next())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := m.Rotate(m.Key().Next())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp, err := first.Verify(mk.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Verify(pp); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := second.Verify(mk.Params); !errors.Is(err, ErrKeyMismatch) {
+		t.Errorf("second record on the first parameters: got %v, want ErrKeyMismatch", err)
+	}
+	if _, err := first.Verify(pp); !errors.Is(err, ErrKeyMismatch) {
+		t.Errorf("first record replayed after it: got %v, want ErrKeyMismatch", err)
+	}
+
+	pairing := mk.Params.Pairing
+	attacker := mk.Next()
+	forgeries := map[string]func(rot *RotationRecord){
+		"value": func(rot *RotationRecord) { rot.Value = pairing.NewG1().Rand() },
+		"epoch": func(rot *RotationRecord) { rot.Epoch++ },
+		"new key": func(rot *RotationRecord) {
+			rot.NewPK1, rot.NewPK2 = attacker.Params.PK1, attacker.Params.PK2
+			rot.NewSignature = pairing.NewG1().PowZn(rot.digest(pairing), attacker.secret)
+		},
+	}
+	for name, forge := range forgeries {
+		rot := *first
+		forge(&rot)
+		if _, err := rot.Verify(mk.Params); !errors.Is(err, ErrInvalidRotation) {
+			t.Errorf("record with a forged %s: got %v, want ErrInvalidRotation", name, err)
+		}
+	}
+}
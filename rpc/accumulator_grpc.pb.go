@@ -0,0 +1,369 @@
+// gRPC API of an accumulator manager and verifier.
+//
+// Group elements are carried in the byte encoding of pbc (Element.Bytes)
+// and must be decoded into the pairing of the public parameters.
+//
+// Regenerate the Go code from the repository root with
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	    --go-grpc_out=. --go-grpc_opt=paths=source_relative rpc/accumulator.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: rpc/accumulator.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AccumulatorService_GetParams_FullMethodName     = "/accumulator.v1.AccumulatorService/GetParams"
+	AccumulatorService_Enroll_FullMethodName        = "/accumulator.v1.AccumulatorService/Enroll"
+	AccumulatorService_Revoke_FullMethodName        = "/accumulator.v1.AccumulatorService/Revoke"
+	AccumulatorService_IssueWitness_FullMethodName  = "/accumulator.v1.AccumulatorService/IssueWitness"
+	AccumulatorService_GetState_FullMethodName      = "/accumulator.v1.AccumulatorService/GetState"
+	AccumulatorService_StreamUpdates_FullMethodName = "/accumulator.v1.AccumulatorService/StreamUpdates"
+	AccumulatorService_Verify_FullMethodName        = "/accumulator.v1.AccumulatorService/Verify"
+)
+
+// AccumulatorServiceClient is the client API for AccumulatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AccumulatorServiceClient interface {
+	GetParams(ctx context.Context, in *GetParamsRequest, opts ...grpc.CallOption) (*PublicParams, error)
+	Enroll(ctx context.Context, in *EnrollRequest, opts ...grpc.CallOption) (*UpdateRecord, error)
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*UpdateRecord, error)
+	IssueWitness(ctx context.Context, in *IssueWitnessRequest, opts ...grpc.CallOption) (*Witness, error)
+	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*State, error)
+	StreamUpdates(ctx context.Context, in *StreamUpdatesRequest, opts ...grpc.CallOption) (AccumulatorService_StreamUpdatesClient, error)
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+}
+
+type accumulatorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccumulatorServiceClient(cc grpc.ClientConnInterface) AccumulatorServiceClient {
+	return &accumulatorServiceClient{cc}
+}
+
+func (c *accumulatorServiceClient) GetParams(ctx context.Context, in *GetParamsRequest, opts ...grpc.CallOption) (*PublicParams, error) {
+	out := new(PublicParams)
+	err := c.cc.Invoke(ctx, AccumulatorService_GetParams_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accumulatorServiceClient) Enroll(ctx context.Context, in *EnrollRequest, opts ...grpc.CallOption) (*UpdateRecord, error) {
+	out := new(UpdateRecord)
+	err := c.cc.Invoke(ctx, AccumulatorService_Enroll_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accumulatorServiceClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*UpdateRecord, error) {
+	out := new(UpdateRecord)
+	err := c.cc.Invoke(ctx, AccumulatorService_Revoke_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accumulatorServiceClient) IssueWitness(ctx context.Context, in *IssueWitnessRequest, opts ...grpc.CallOption) (*Witness, error) {
+	out := new(Witness)
+	err := c.cc.Invoke(ctx, AccumulatorService_IssueWitness_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accumulatorServiceClient) GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*State, error) {
+	out := new(State)
+	err := c.cc.Invoke(ctx, AccumulatorService_GetState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accumulatorServiceClient) StreamUpdates(ctx context.Context, in *StreamUpdatesRequest, opts ...grpc.CallOption) (AccumulatorService_StreamUpdatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AccumulatorService_ServiceDesc.Streams[0], AccumulatorService_StreamUpdates_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &accumulatorServiceStreamUpdatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AccumulatorService_StreamUpdatesClient interface {
+	Recv() (*UpdateRecord, error)
+	grpc.ClientStream
+}
+
+type accumulatorServiceStreamUpdatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *accumulatorServiceStreamUpdatesClient) Recv() (*UpdateRecord, error) {
+	m := new(UpdateRecord)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *accumulatorServiceClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, AccumulatorService_Verify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccumulatorServiceServer is the server API for AccumulatorService service.
+// All implementations must embed UnimplementedAccumulatorServiceServer
+// for forward compatibility
+type AccumulatorServiceServer interface {
+	GetParams(context.Context, *GetParamsRequest) (*PublicParams, error)
+	Enroll(context.Context, *EnrollRequest) (*UpdateRecord, error)
+	Revoke(context.Context, *RevokeRequest) (*UpdateRecord, error)
+	IssueWitness(context.Context, *IssueWitnessRequest) (*Witness, error)
+	GetState(context.Context, *GetStateRequest) (*State, error)
+	StreamUpdates(*StreamUpdatesRequest, AccumulatorService_StreamUpdatesServer) error
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	mustEmbedUnimplementedAccumulatorServiceServer()
+}
+
+// UnimplementedAccumulatorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAccumulatorServiceServer struct {
+}
+
+func (UnimplementedAccumulatorServiceServer) GetParams(context.Context, *GetParamsRequest) (*PublicParams, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetParams not implemented")
+}
+func (UnimplementedAccumulatorServiceServer) Enroll(context.Context, *EnrollRequest) (*UpdateRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Enroll not implemented")
+}
+func (UnimplementedAccumulatorServiceServer) Revoke(context.Context, *RevokeRequest) (*UpdateRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (UnimplementedAccumulatorServiceServer) IssueWitness(context.Context, *IssueWitnessRequest) (*Witness, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueWitness not implemented")
+}
+func (UnimplementedAccumulatorServiceServer) GetState(context.Context, *GetStateRequest) (*State, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedAccumulatorServiceServer) StreamUpdates(*StreamUpdatesRequest, AccumulatorService_StreamUpdatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamUpdates not implemented")
+}
+func (UnimplementedAccumulatorServiceServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedAccumulatorServiceServer) mustEmbedUnimplementedAccumulatorServiceServer() {}
+
+// UnsafeAccumulatorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccumulatorServiceServer will
+// result in compilation errors.
+type UnsafeAccumulatorServiceServer interface {
+	mustEmbedUnimplementedAccumulatorServiceServer()
+}
+
+func RegisterAccumulatorServiceServer(s grpc.ServiceRegistrar, srv AccumulatorServiceServer) {
+	s.RegisterService(&AccumulatorService_ServiceDesc, srv)
+}
+
+func _AccumulatorService_GetParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccumulatorServiceServer).GetParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccumulatorService_GetParams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccumulatorServiceServer).GetParams(ctx, req.(*GetParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccumulatorService_Enroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccumulatorServiceServer).Enroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccumulatorService_Enroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccumulatorServiceServer).Enroll(ctx, req.(*EnrollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccumulatorService_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccumulatorServiceServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccumulatorService_Revoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccumulatorServiceServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccumulatorService_IssueWitness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueWitnessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccumulatorServiceServer).IssueWitness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccumulatorService_IssueWitness_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccumulatorServiceServer).IssueWitness(ctx, req.(*IssueWitnessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccumulatorService_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccumulatorServiceServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccumulatorService_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccumulatorServiceServer).GetState(ctx, req.(*GetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccumulatorService_StreamUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AccumulatorServiceServer).StreamUpdates(m, &accumulatorServiceStreamUpdatesServer{stream})
+}
+
+type AccumulatorService_StreamUpdatesServer interface {
+	Send(*UpdateRecord) error
+	grpc.ServerStream
+}
+
+type accumulatorServiceStreamUpdatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *accumulatorServiceStreamUpdatesServer) Send(m *UpdateRecord) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AccumulatorService_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccumulatorServiceServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccumulatorService_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccumulatorServiceServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AccumulatorService_ServiceDesc is the grpc.ServiceDesc for AccumulatorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AccumulatorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "accumulator.v1.AccumulatorService",
+	HandlerType: (*AccumulatorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetParams",
+			Handler:    _AccumulatorService_GetParams_Handler,
+		},
+		{
+			MethodName: "Enroll",
+			Handler:    _AccumulatorService_Enroll_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _AccumulatorService_Revoke_Handler,
+		},
+		{
+			MethodName: "IssueWitness",
+			Handler:    _AccumulatorService_IssueWitness_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _AccumulatorService_GetState_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _AccumulatorService_Verify_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamUpdates",
+			Handler:       _AccumulatorService_StreamUpdates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/accumulator.proto",
+}
@@ -0,0 +1,212 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/Nik-U/pbc"
+
+	"github.com/neucc1997/Accumulator"
+)
+
+// This file converts between the messages of the schema and the types of
+// the accumulator package. Decoding needs the public parameters, as the
+// elements must belong to their pairing.
+
+// setElement sets el from b with the checks of the accumulator encodings,
+// see accumulator.SetElementBytes.
+func setElement(el *pbc.Element, b []byte) (*pbc.Element, error) {
+	if err := accumulator.SetElementBytes(el, b); err != nil {
+		return nil, err
+	}
+	return el, nil
+}
+
+func elementsBytes(list []*pbc.Element) [][]byte {
+	if len(list) == 0 {
+		return nil
+	}
+	encoded := make([][]byte, len(list))
+	for i, el := range list {
+		encoded[i] = el.Bytes()
+	}
+	return encoded
+}
+
+func setElements(encoded [][]byte, newElement func() *pbc.Element) ([]*pbc.Element, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	list := make([]*pbc.Element, len(encoded))
+	for i, b := range encoded {
+		var err error
+		if list[i], err = setElement(newElement(), b); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// FromParams converts public parameters to their message.
+func FromParams(pp *accumulator.PublicParams) *PublicParams {
+	return &PublicParams{
+		Params: pp.Params,
+		G:      pp.G.Bytes(),
+		H:      pp.H.Bytes(),
+		Pk1:    pp.PK1.Bytes(),
+		Pk2:    pp.PK2.Bytes(),
+	}
+}
+
+// ToParams rebuilds the pairing and the elements of msg and validates the
+// result.
+func ToParams(msg *PublicParams) (*accumulator.PublicParams, error) {
+	pairing, err := pbc.NewPairingFromString(msg.GetParams())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", accumulator.ErrInvalidEncoding, err)
+	}
+	pp := &accumulator.PublicParams{Params: msg.GetParams(), Pairing: pairing}
+	for _, f := range []struct {
+		dst **pbc.Element
+		el  *pbc.Element
+		b   []byte
+	}{
+		{&pp.G, pairing.NewG1(), msg.GetG()},
+		{&pp.H, pairing.NewG2(), msg.GetH()},
+		{&pp.PK1, pairing.NewG1(), msg.GetPk1()},
+		{&pp.PK2, pairing.NewG2(), msg.GetPk2()},
+	} {
+		if *f.dst, err = setElement(f.el, f.b); err != nil {
+			return nil, err
+		}
+	}
+	if err := pp.Validate(); err != nil {
+		return nil, err
+	}
+	return pp, nil
+}
+
+// FromAccumulator converts an accumulator to its message.
+func FromAccumulator(acc *accumulator.Accumulator) *Accumulator {
+	return &Accumulator{Epoch: acc.Epoch(), Value: acc.Value().Bytes()}
+}
+
+// ToAccumulator decodes msg into the pairing of pp.
+func ToAccumulator(pp *accumulator.PublicParams, msg *Accumulator) (*accumulator.Accumulator, error) {
+	value, err := setElement(pp.Pairing.NewG1(), msg.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	return accumulator.NewAccumulatorAt(value, msg.GetEpoch()), nil
+}
+
+// FromWitness converts a witness to its message.
+func FromWitness(wit *accumulator.Witness) *Witness {
+	return &Witness{
+		Value:       wit.Value().Bytes(),
+		Element:     wit.Element().Bytes(),
+		Accumulator: FromAccumulator(wit.Accumulator()),
+	}
+}
+
+// ToWitness decodes msg into the pairing of pp. Like
+// PublicParams.DecodeWitness it checks the witness against the accumulator
+// it carries.
+func ToWitness(pp *accumulator.PublicParams, msg *Witness) (*accumulator.Witness, error) {
+	value, err := setElement(pp.Pairing.NewG1(), msg.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	element, err := setElement(pp.Pairing.NewZr(), msg.GetElement())
+	if err != nil {
+		return nil, err
+	}
+	acc, err := ToAccumulator(pp, msg.GetAccumulator())
+	if err != nil {
+		return nil, err
+	}
+	wit := accumulator.NewWitness(value, element, acc)
+	if !accumulator.VerifyWitness(wit, acc, pp.H, pp.PK2, element, pp.Pairing) {
+		return nil, accumulator.ErrInvalidWitness
+	}
+	return wit, nil
+}
+
+// FromRecord converts an update record to its message.
+func FromRecord(rec *accumulator.UpdateRecord) *UpdateRecord {
+	return &UpdateRecord{
+		Epoch:   rec.Epoch,
+		Deleted: elementsBytes(rec.Deleted),
+		Added:   elementsBytes(rec.Added),
+		Steps:   elementsBytes(rec.Steps),
+		Value:   rec.Value.Bytes(),
+		Powers:  elementsBytes(rec.Powers),
+	}
+}
+
+// ToRecord decodes msg into the pairing of pp.
+func ToRecord(pp *accumulator.PublicParams, msg *UpdateRecord) (*accumulator.UpdateRecord, error) {
+	rec := &accumulator.UpdateRecord{Epoch: msg.GetEpoch()}
+	var err error
+	if rec.Deleted, err = setElements(msg.GetDeleted(), pp.Pairing.NewZr); err != nil {
+		return nil, err
+	}
+	if rec.Added, err = setElements(msg.GetAdded(), pp.Pairing.NewZr); err != nil {
+		return nil, err
+	}
+	if rec.Steps, err = setElements(msg.GetSteps(), pp.Pairing.NewG1); err != nil {
+		return nil, err
+	}
+	if rec.Value, err = setElement(pp.Pairing.NewG1(), msg.GetValue()); err != nil {
+		return nil, err
+	}
+	if rec.Powers, err = setElements(msg.GetPowers(), pp.Pairing.NewG1); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// FromSignature converts a member signature on message, made against the
+// accumulator at epoch, to a membership proof.
+func FromSignature(sig *accumulator.MemberSignature, message []byte, epoch uint64) *MembershipProof {
+	return &MembershipProof{
+		Epoch:   epoch,
+		Message: message,
+		Wbar:    sig.WBar.Bytes(),
+		Vbar:    sig.VBar.Bytes(),
+		C:       sig.C.Bytes(),
+		S1:      sig.S1.Bytes(),
+		S2:      sig.S2.Bytes(),
+	}
+}
+
+// ToSignature decodes the signature of msg into the pairing of pp.
+func ToSignature(pp *accumulator.PublicParams, msg *MembershipProof) (*accumulator.MemberSignature, error) {
+	sig := new(accumulator.MemberSignature)
+	for _, f := range []struct {
+		dst **pbc.Element
+		el  *pbc.Element
+		b   []byte
+	}{
+		{&sig.WBar, pp.Pairing.NewG1(), msg.GetWbar()},
+		{&sig.VBar, pp.Pairing.NewG1(), msg.GetVbar()},
+		{&sig.C, pp.Pairing.NewZr(), msg.GetC()},
+		{&sig.S1, pp.Pairing.NewZr(), msg.GetS1()},
+		{&sig.S2, pp.Pairing.NewZr(), msg.GetS2()},
+	} {
+		var err error
+		if *f.dst, err = setElement(f.el, f.b); err != nil {
+			return nil, err
+		}
+	}
+	return sig, nil
+}
+
+// FromMember converts member content to its message.
+func FromMember(content accumulator.AccumulatorContent) *Member {
+	return &Member{PublicKey: content.PublicKey, Attributes: content.Attributes, Role: content.Role}
+}
+
+// ToMember converts msg to member content.
+func ToMember(msg *Member) accumulator.AccumulatorContent {
+	return accumulator.AccumulatorContent{PublicKey: msg.GetPublicKey(), Attributes: msg.GetAttributes(), Role: msg.GetRole()}
+}
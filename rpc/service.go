@@ -0,0 +1,246 @@
+// Package rpc is the gRPC API of an accumulator manager. The schema is in
+// accumulator.proto; Service implements it on top of a Manager, and the
+// From and To functions convert between its messages and the types of the
+// accumulator package.
+//
+// Enroll, Revoke and IssueWitness are authorized like the matching requests
+// of package server, whose documentation says why that is required for any
+// use beyond reading; a service without an Authorizer refuses them with
+// PermissionDenied. The other calls only serve public data and are open.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/neucc1997/Accumulator"
+	"github.com/neucc1997/Accumulator/server"
+)
+
+var (
+	ErrUnauthenticated = errors.New("rpc: call is not authenticated")
+	ErrForbidden       = errors.New("rpc: call is not allowed")
+)
+
+// Authorizer is server.Authorizer for gRPC calls, whose metadata and peer
+// are in ctx. Errors wrapping ErrUnauthenticated are answered with
+// Unauthenticated, gRPC status errors with their own code, and all others
+// with PermissionDenied.
+type Authorizer func(ctx context.Context, action server.Action, publicKey string) error
+
+// BearerToken is server.BearerToken for calls with the metadata
+// "authorization: Bearer token".
+func BearerToken(token string) Authorizer {
+	return func(ctx context.Context, action server.Action, publicKey string) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, got := range md.Get("authorization") {
+			if server.BearerMatches(got, token) {
+				return nil
+			}
+		}
+		return ErrUnauthenticated
+	}
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithAuthorizer makes auth decide on Enroll, Revoke and IssueWitness.
+func WithAuthorizer(auth Authorizer) Option {
+	return func(s *Service) {
+		s.auth = auth
+	}
+}
+
+// Service serves a Manager over gRPC. Calls are serialized by a mutex, as
+// the manager is not safe for concurrent use.
+type Service struct {
+	UnimplementedAccumulatorServiceServer
+
+	mu   sync.Mutex
+	m    *accumulator.Manager
+	auth Authorizer
+}
+
+// NewService returns a service for m. The caller keeps ownership of m and
+// must not use it while the service runs. Without WithAuthorizer the
+// service refuses every Enroll, Revoke and IssueWitness call.
+func NewService(m *accumulator.Manager, opts ...Option) *Service {
+	s := &Service{m: m}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// authorize runs the authorizer for action and returns the status error of
+// a refusal.
+func (s *Service) authorize(ctx context.Context, action server.Action, publicKey string) error {
+	if s.auth == nil {
+		return status.Error(codes.PermissionDenied, ErrForbidden.Error())
+	}
+	err := s.auth(ctx, action, publicKey)
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, ErrUnauthenticated) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return status.Error(codes.PermissionDenied, err.Error())
+}
+
+// Rotate rotates the key of the manager to next while the service runs;
+// see Manager.Rotate.
+func (s *Service) Rotate(next *accumulator.ManagerKey) (*accumulator.RotationRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.Rotate(next)
+}
+
+func (s *Service) GetParams(ctx context.Context, req *GetParamsRequest) (*PublicParams, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return FromParams(s.m.Params()), nil
+}
+
+func (s *Service) Enroll(ctx context.Context, req *EnrollRequest) (*UpdateRecord, error) {
+	if req.GetMember().GetPublicKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "public key is required")
+	}
+	if err := s.authorize(ctx, server.ActionEnroll, req.GetMember().GetPublicKey()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	rec, err := s.m.Add(ToMember(req.GetMember()))
+	s.mu.Unlock()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return FromRecord(rec), nil
+}
+
+func (s *Service) Revoke(ctx context.Context, req *RevokeRequest) (*UpdateRecord, error) {
+	if err := s.authorize(ctx, server.ActionRevoke, req.GetPublicKey()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	rec, err := s.m.DeletePublicKey(req.GetPublicKey())
+	s.mu.Unlock()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return FromRecord(rec), nil
+}
+
+func (s *Service) IssueWitness(ctx context.Context, req *IssueWitnessRequest) (*Witness, error) {
+	if err := s.authorize(ctx, server.ActionWitness, req.GetPublicKey()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	member, ok := s.m.MemberByPublicKey(req.GetPublicKey())
+	if !ok {
+		return nil, statusError(accumulator.ErrUnknownMember)
+	}
+	wit, err := s.m.Witness(member.Content)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return FromWitness(wit), nil
+}
+
+func (s *Service) GetState(ctx context.Context, req *GetStateRequest) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &State{
+		Accumulator: FromAccumulator(s.m.Accumulator()),
+		MemberCount: uint64(s.m.Count()),
+		FirstEpoch:  s.m.History().FirstEpoch(),
+	}, nil
+}
+
+// StreamUpdates sends the records after the requested epoch and then every
+// new record until the client goes away or the key is rotated.
+func (s *Service) StreamUpdates(req *StreamUpdatesRequest, stream AccumulatorService_StreamUpdatesServer) error {
+	s.mu.Lock()
+	history := s.m.History()
+	s.mu.Unlock()
+	updates, err := history.Subscribe(stream.Context(), req.GetSinceEpoch())
+	if err != nil {
+		return statusError(err)
+	}
+	for rec := range updates {
+		if err := stream.Send(FromRecord(rec)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify checks a witness or a membership proof against the accumulator
+// the manager published at its epoch.
+func (s *Service) Verify(ctx context.Context, req *VerifyRequest) (*VerifyResponse, error) {
+	s.mu.Lock()
+	pp := s.m.Params()
+	s.mu.Unlock()
+	switch subject := req.GetSubject().(type) {
+	case *VerifyRequest_Witness:
+		wit, err := ToWitness(pp, subject.Witness)
+		if errors.Is(err, accumulator.ErrInvalidWitness) {
+			return &VerifyResponse{Epoch: subject.Witness.GetAccumulator().GetEpoch()}, nil
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return s.verifyAt(wit.Epoch(), func(acc *accumulator.Accumulator) bool {
+			return acc.Equals(wit.Accumulator())
+		}), nil
+	case *VerifyRequest_Proof:
+		sig, err := ToSignature(pp, subject.Proof)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return s.verifyAt(subject.Proof.GetEpoch(), func(acc *accumulator.Accumulator) bool {
+			return accumulator.VerifyMemberSignature(subject.Proof.GetMessage(), sig, acc, pp)
+		}), nil
+	default:
+		return nil, status.Error(codes.InvalidArgument, "nothing to verify")
+	}
+}
+
+// verifyAt runs check on the accumulator published at epoch. Epochs
+// outside the history are reported as invalid.
+func (s *Service) verifyAt(epoch uint64, check func(*accumulator.Accumulator) bool) *VerifyResponse {
+	s.mu.Lock()
+	published, err := s.m.History().ValueAt(epoch)
+	latest := s.m.History().LatestEpoch()
+	s.mu.Unlock()
+	resp := &VerifyResponse{Epoch: epoch}
+	if err == nil && check(published) {
+		resp.Valid = true
+		resp.Current = epoch == latest
+	}
+	return resp
+}
+
+// statusError maps the errors of the manager to gRPC status codes.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, accumulator.ErrDuplicateMember):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, accumulator.ErrUnknownMember):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, accumulator.ErrUnknownEpoch):
+		return status.Error(codes.OutOfRange, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
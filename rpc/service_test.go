@@ -0,0 +1,304 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/neucc1997/Accumulator"
+	"github.com/neucc1997/Accumulator/server"
+)
+
+const testToken = "secret"
+
+// newTestClient serves a fresh manager over bufconn and returns a client
+// for it.
+func newTestClient(t *testing.T, opts ...Option) (*Service, AccumulatorServiceClient) {
+	t.Helper()
+	mk, err := accumulator.SetupManagerKeyNamed("a-80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(accumulator.NewManager(mk), opts...)
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterAccumulatorServiceServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return s, NewAccumulatorServiceClient(conn)
+}
+
+func operator() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testToken)
+}
+
+func params(t *testing.T, client AccumulatorServiceClient) *accumulator.PublicParams {
+	t.Helper()
+	msg, err := client.GetParams(context.Background(), &GetParamsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp, err := ToParams(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pp
+}
+
+func TestEnrollAndVerify(t *testing.T) {
+	_, client := newTestClient(t, WithAuthorizer(BearerToken(testToken)))
+	ctx := operator()
+	pp := params(t, client)
+
+	for _, pk := range []string{"alice", "bob"} {
+		if _, err := client.Enroll(ctx, &EnrollRequest{Member: &Member{PublicKey: pk, Role: "user"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := client.Enroll(ctx, &EnrollRequest{Member: &Member{PublicKey: "bob"}})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("duplicate enrollment: got %v, want AlreadyExists", err)
+	}
+	_, err = client.Enroll(ctx, &EnrollRequest{Member: &Member{}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("enrollment without public key: got %v, want InvalidArgument", err)
+	}
+
+	witMsg, err := client.IssueWitness(ctx, &IssueWitnessRequest{PublicKey: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wit, err := ToWitness(pp, witMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.IssueWitness(ctx, &IssueWitnessRequest{PublicKey: "carol"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("witness of unknown member: got %v, want NotFound", err)
+	}
+
+	sig := accumulator.SignAsMember([]byte("hello"), wit, wit.Element())
+	proof := FromSignature(sig, []byte("hello"), wit.Epoch())
+	resp, err := client.Verify(context.Background(), &VerifyRequest{Subject: &VerifyRequest_Proof{Proof: proof}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Valid || !resp.Current || resp.Epoch != 2 {
+		t.Errorf("proof: got %v", resp)
+	}
+	proof.Message = []byte("other")
+	if resp, err = client.Verify(context.Background(), &VerifyRequest{Subject: &VerifyRequest_Proof{Proof: proof}}); err != nil || resp.Valid {
+		t.Errorf("proof of another message: got %v, %v", resp, err)
+	}
+
+	if _, err := client.Revoke(ctx, &RevokeRequest{PublicKey: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Revoke(ctx, &RevokeRequest{PublicKey: "alice"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("second revocation: got %v, want NotFound", err)
+	}
+	resp, err = client.Verify(context.Background(), &VerifyRequest{Subject: &VerifyRequest_Witness{Witness: FromWitness(wit)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Valid || resp.Current || resp.Epoch != 2 {
+		t.Errorf("outdated witness: got %v", resp)
+	}
+	_, err = client.Verify(context.Background(), &VerifyRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty verify: got %v, want InvalidArgument", err)
+	}
+
+	state, err := client.GetState(context.Background(), &GetStateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.MemberCount != 1 || state.Accumulator.Epoch != 3 || state.FirstEpoch != 0 {
+		t.Errorf("state: got %v", state)
+	}
+}
+
+func TestVerifyRejectsMauledWitness(t *testing.T) {
+	_, client := newTestClient(t, WithAuthorizer(BearerToken(testToken)))
+	ctx := operator()
+	pp := params(t, client)
+	pairing := pp.Pairing
+	if _, err := client.Enroll(ctx, &EnrollRequest{Member: &Member{PublicKey: "alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	witMsg, err := client.IssueWitness(ctx, &IssueWitnessRequest{PublicKey: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The point (0, 0) of order 2 pairs to the identity with everything, so
+	// the mauled witness W * (0, 0) passes the pairing check of its own.
+	p := pairing.NewG1().SetBytes(make([]byte, pairing.G1Length()))
+	w := pairing.NewG1().SetBytes(witMsg.Value)
+	mauled := &Witness{
+		Value:       pairing.NewG1().Add(w, p).Bytes(),
+		Element:     witMsg.Element,
+		Accumulator: witMsg.Accumulator,
+	}
+	if _, err := ToWitness(pp, mauled); !errors.Is(err, accumulator.ErrNotInSubgroup) {
+		t.Errorf("ToWitness of a mauled witness: got %v, want ErrNotInSubgroup", err)
+	}
+	resp, err := client.Verify(context.Background(), &VerifyRequest{Subject: &VerifyRequest_Witness{Witness: mauled}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("verify a mauled witness: got %v, %v, want InvalidArgument", resp, err)
+	}
+	identity := &Witness{
+		Value:       witMsg.Value,
+		Element:     witMsg.Element,
+		Accumulator: &Accumulator{Epoch: 1, Value: pairing.NewG1().Set0().Bytes()},
+	}
+	if _, err := ToWitness(pp, identity); !errors.Is(err, accumulator.ErrInvalidEncoding) {
+		t.Errorf("ToWitness with the identity as accumulator: got %v, want ErrInvalidEncoding", err)
+	}
+	if resp, err := client.Verify(context.Background(), &VerifyRequest{Subject: &VerifyRequest_Witness{Witness: witMsg}}); err != nil || !resp.Valid {
+		t.Errorf("verify the witness: got %v, %v", resp, err)
+	}
+}
+
+func TestStreamUpdates(t *testing.T) {
+	_, client := newTestClient(t, WithAuthorizer(BearerToken(testToken)))
+	ctx := operator()
+	pp := params(t, client)
+	for _, pk := range []string{"alice", "bob"} {
+		if _, err := client.Enroll(ctx, &EnrollRequest{Member: &Member{PublicKey: pk}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamUpdates(streamCtx, &StreamUpdatesRequest{SinceEpoch: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Revoke(ctx, &RevokeRequest{PublicKey: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, epoch := range []uint64{2, 3} {
+		msg, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec, err := ToRecord(pp, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Epoch != epoch {
+			t.Fatalf("got record %d, want %d", rec.Epoch, epoch)
+		}
+	}
+
+	stream, err = client.StreamUpdates(context.Background(), &StreamUpdatesRequest{SinceEpoch: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.OutOfRange {
+		t.Errorf("stream from a future epoch: got %v, want OutOfRange", err)
+	}
+}
+
+func TestStreamEndsAtRotation(t *testing.T) {
+	s, client := newTestClient(t, WithAuthorizer(BearerToken(testToken)))
+	stream, err := client.StreamUpdates(context.Background(), &StreamUpdatesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Enroll(operator(), &EnrollRequest{Member: &Member{PublicKey: "alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Rotate(s.m.Key().Next()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("stream after rotation: got %v, want EOF", err)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	calls := []struct {
+		name string
+		call func(context.Context, AccumulatorServiceClient) error
+	}{
+		{"Enroll", func(ctx context.Context, c AccumulatorServiceClient) error {
+			_, err := c.Enroll(ctx, &EnrollRequest{Member: &Member{PublicKey: "alice"}})
+			return err
+		}},
+		{"IssueWitness", func(ctx context.Context, c AccumulatorServiceClient) error {
+			_, err := c.IssueWitness(ctx, &IssueWitnessRequest{PublicKey: "alice"})
+			return err
+		}},
+		{"Revoke", func(ctx context.Context, c AccumulatorServiceClient) error {
+			_, err := c.Revoke(ctx, &RevokeRequest{PublicKey: "alice"})
+			return err
+		}},
+	}
+
+	_, open := newTestClient(t)
+	for _, c := range calls {
+		if err := c.call(operator(), open); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s without authorizer: got %v, want PermissionDenied", c.name, err)
+		}
+	}
+
+	_, client := newTestClient(t, WithAuthorizer(BearerToken(testToken)))
+	wrong := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	for _, c := range calls {
+		for _, ctx := range []context.Context{context.Background(), wrong} {
+			if err := c.call(ctx, client); status.Code(err) != codes.Unauthenticated {
+				t.Errorf("%s without the token: got %v, want Unauthenticated", c.name, err)
+			}
+		}
+		if err := c.call(operator(), client); err != nil {
+			t.Errorf("%s with the token: %v", c.name, err)
+		}
+	}
+
+	_, client = newTestClient(t, WithAuthorizer(func(ctx context.Context, action server.Action, publicKey string) error {
+		switch {
+		case action == server.ActionRevoke:
+			return status.Error(codes.FailedPrecondition, "revocations are closed")
+		case publicKey != "alice":
+			return errors.New("not alice")
+		}
+		return nil
+	}))
+	if err := calls[0].call(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.IssueWitness(context.Background(), &IssueWitnessRequest{PublicKey: "bob"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("witness of bob: got %v, want PermissionDenied", err)
+	}
+	if err := calls[2].call(context.Background(), client); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("revocation: got %v, want FailedPrecondition", err)
+	}
+	if _, err := client.GetState(context.Background(), &GetStateRequest{}); err != nil {
+		t.Errorf("GetState: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neucc1997/Accumulator"
+)
+
+const (
+	minRetryDelay = time.Second
+	maxRetryDelay = 30 * time.Second
+)
+
+// ErrFeedGone means that the server no longer has the records after the
+// epoch of the member; the witness has to be issued anew.
+var ErrFeedGone = errors.New("server: update records are no longer available")
+
+// Follow keeps mc current with the event stream of the server at baseURL.
+// After a lost connection it reconnects with growing delays and resumes
+// after the epoch of the witness. It returns when ctx is done, when the
+// member is revoked (accumulator.ErrWitnessRevoked), or when a record
+// cannot be applied. client may be nil to use http.DefaultClient.
+func Follow(ctx context.Context, client *http.Client, baseURL string, mc *accumulator.MemberClient) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	delay := minRetryDelay
+	for {
+		received, err := followOnce(ctx, client, strings.TrimSuffix(baseURL, "/")+"/events", mc)
+		var pe *permanentError
+		if errors.As(err, &pe) {
+			return pe.err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if received {
+			delay = minRetryDelay
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// permanentError marks errors that reconnecting does not cure.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// followOnce reads one connection of the event stream. It reports whether
+// any record arrived.
+func followOnce(ctx context.Context, client *http.Client, url string, mc *accumulator.MemberClient) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, &permanentError{err}
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(mc.Epoch(), 10))
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusGone:
+		return false, &permanentError{ErrFeedGone}
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("server: %s", resp.Status)
+	}
+
+	received := false
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 1<<24)
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			rec, err := mc.Params().DecodeUpdateRecord(data.Bytes())
+			data.Reset()
+			if err != nil {
+				return received, &permanentError{err}
+			}
+			if err := mc.Receive(rec); err != nil {
+				return received, &permanentError{err}
+			}
+			received = true
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	return received, scanner.Err()
+}
@@ -0,0 +1,418 @@
+// Package server exposes an accumulator manager over HTTP with JSON
+// bodies.
+//
+// Endpoints:
+//
+//	POST   /members                    enroll the AccumulatorContent in the body
+//	DELETE /members/{publicKey}        revoke a member
+//	GET    /members/{publicKey}/witness issue a witness for a member
+//	GET    /accumulator                current accumulator and epoch
+//	GET    /records?since={epoch}      update records after an epoch
+//	GET    /events?since={epoch}       stream of update records as server-sent events
+//	GET    /rotations                  records of the key rotations
+//	POST   /verify                     verify the witness in the body
+//
+// Enrollment and revocation answer with the published update record.
+// Errors are answered with {"error": "..."} and a matching status code.
+//
+// Enrollment, revocation and witnesses must pass the Authorizer given with
+// WithAuthorizer; a server without one refuses them with 403 Forbidden.
+// Setting an authorizer is required for any use beyond reading: the element
+// of a member is the public hash of its content, so whoever obtains its
+// witness can sign as the member. The other endpoints only serve public
+// data and are open.
+//
+// /events sends each record as an "update" event whose id is the epoch of
+// the record and whose data is its JSON encoding. A client that reconnects
+// with the Last-Event-ID header resumes after that epoch. A key rotation
+// ends the stream; resuming from an epoch before the rotation fails with
+// 410 Gone, and the member has to fetch a new witness.
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neucc1997/Accumulator"
+)
+
+const (
+	// maxBodySize bounds request bodies; contents and witnesses are small.
+	maxBodySize = 1 << 20
+
+	// keepAliveInterval is the time between comments sent on an idle event
+	// stream, so that proxies do not close it.
+	keepAliveInterval = 30 * time.Second
+)
+
+var (
+	ErrUnauthenticated = errors.New("server: request is not authenticated")
+	ErrForbidden       = errors.New("server: request is not allowed")
+)
+
+// Action is a request that changes the member set or hands out a witness.
+// The gRPC service of package rpc authorizes its calls with the same
+// actions.
+type Action string
+
+const (
+	ActionEnroll  Action = "enroll"
+	ActionRevoke  Action = "revoke"
+	ActionWitness Action = "witness"
+)
+
+// Authorizer decides whether r may perform action on the member with
+// publicKey, and returns nil to allow it. Errors wrapping
+// ErrUnauthenticated are answered with 401 Unauthorized, all others with
+// 403 Forbidden. An authorizer that lets members fetch witnesses must only
+// hand each member its own.
+type Authorizer func(r *http.Request, action Action, publicKey string) error
+
+// BearerToken returns an Authorizer that allows every action to requests
+// with the header "Authorization: Bearer token", for a server whose
+// privileged endpoints are used by a single operator.
+func BearerToken(token string) Authorizer {
+	return func(r *http.Request, action Action, publicKey string) error {
+		if !BearerMatches(r.Header.Get("Authorization"), token) {
+			return ErrUnauthenticated
+		}
+		return nil
+	}
+}
+
+// BearerMatches reports whether the credentials value are "Bearer token".
+// The comparison takes the same time wherever they differ.
+func BearerMatches(value, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(value), []byte("Bearer "+token)) == 1
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAuthorizer makes auth decide on enrollment, revocation and witness
+// requests.
+func WithAuthorizer(auth Authorizer) Option {
+	return func(s *Server) {
+		s.auth = auth
+	}
+}
+
+// Server serves a Manager. Requests are serialized by a mutex, as the
+// manager is not safe for concurrent use.
+type Server struct {
+	mu   sync.Mutex
+	m    *accumulator.Manager
+	mux  *http.ServeMux
+	auth Authorizer
+}
+
+// New returns a server for m. The caller keeps ownership of m and must not
+// use it while the server runs. Without WithAuthorizer the server refuses
+// every enrollment, revocation and witness request.
+func New(m *accumulator.Manager, opts ...Option) *Server {
+	s := &Server{m: m, mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("/members", s.handleEnroll)
+	s.mux.HandleFunc("/members/", s.handleMember)
+	s.mux.HandleFunc("/accumulator", s.handleAccumulator)
+	s.mux.HandleFunc("/records", s.handleRecords)
+	s.mux.HandleFunc("/events", s.handleEvents)
+	s.mux.HandleFunc("/rotations", s.handleRotations)
+	s.mux.HandleFunc("/verify", s.handleVerify)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Rotate rotates the key of the manager to next while the server runs; see
+// Manager.Rotate.
+func (s *Server) Rotate(next *accumulator.ManagerKey) (*accumulator.RotationRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.Rotate(next)
+}
+
+// authorize runs the authorizer for action and answers the request if it
+// is refused.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, action Action, publicKey string) bool {
+	err := ErrForbidden
+	if s.auth != nil {
+		err = s.auth(r, action, publicKey)
+	}
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrUnauthenticated):
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, err)
+	default:
+		writeError(w, http.StatusForbidden, err)
+	}
+	return false
+}
+
+// VerifyResponse is the answer to POST /verify. Valid means that the
+// witness matches an accumulator value the manager published at the
+// witness's epoch; Current that this epoch is the latest one.
+type VerifyResponse struct {
+	Valid   bool   `json:"valid"`
+	Epoch   uint64 `json:"epoch"`
+	Current bool   `json:"current"`
+}
+
+func (s *Server) handleEnroll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var content accumulator.AccumulatorContent
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&content); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if content.PublicKey == "" {
+		writeError(w, http.StatusBadRequest, errors.New("publicKey is required"))
+		return
+	}
+	if !s.authorize(w, r, ActionEnroll, content.PublicKey) {
+		return
+	}
+	s.mu.Lock()
+	rec, err := s.m.Add(content)
+	s.mu.Unlock()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, rec)
+}
+
+// handleMember serves /members/{publicKey} and /members/{publicKey}/witness.
+func (s *Server) handleMember(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.EscapedPath(), "/members/")
+	witness := strings.HasSuffix(rest, "/witness")
+	escaped := strings.TrimSuffix(rest, "/witness")
+	publicKey, err := url.PathUnescape(escaped)
+	if err != nil || publicKey == "" || strings.Contains(escaped, "/") {
+		writeError(w, http.StatusNotFound, errors.New("no such resource"))
+		return
+	}
+	if witness {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		if !s.authorize(w, r, ActionWitness, publicKey) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		member, ok := s.m.MemberByPublicKey(publicKey)
+		if !ok {
+			writeError(w, http.StatusNotFound, accumulator.ErrUnknownMember)
+			return
+		}
+		wit, err := s.m.Witness(member.Content)
+		if err != nil {
+			writeManagerError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, wit)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+	if !s.authorize(w, r, ActionRevoke, publicKey) {
+		return
+	}
+	s.mu.Lock()
+	rec, err := s.m.DeletePublicKey(publicKey)
+	s.mu.Unlock()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rec)
+}
+
+func (s *Server) handleAccumulator(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	s.mu.Lock()
+	acc := s.m.Accumulator()
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (s *Server) handleRecords(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	since, err := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("since must be an epoch"))
+		return
+	}
+	s.mu.Lock()
+	records, err := s.m.History().RecordsSince(since)
+	s.mu.Unlock()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	if records == nil {
+		records = []*accumulator.UpdateRecord{}
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	since := r.Header.Get("Last-Event-ID")
+	if since == "" {
+		since = r.URL.Query().Get("since")
+	}
+	epoch, err := strconv.ParseUint(since, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("since must be an epoch"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+	s.mu.Lock()
+	history := s.m.History()
+	s.mu.Unlock()
+	updates, err := history.Subscribe(r.Context(), epoch)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case rec, ok := <-updates:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(rec)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: update\ndata: %s\n\n", rec.Epoch, data); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func (s *Server) handleRotations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	s.mu.Lock()
+	rotations := s.m.Rotations()
+	s.mu.Unlock()
+	if rotations == nil {
+		rotations = []*accumulator.RotationRecord{}
+	}
+	writeJSON(w, http.StatusOK, rotations)
+}
+
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	// Decoding checks every point, so it runs outside the lock; the
+	// parameters are read under it as Rotate replaces them.
+	s.mu.Lock()
+	pp := s.m.Params()
+	s.mu.Unlock()
+	wit, err := pp.DecodeWitness(data)
+	if errors.Is(err, accumulator.ErrInvalidWitness) {
+		writeJSON(w, http.StatusOK, VerifyResponse{})
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	resp := VerifyResponse{Epoch: wit.Epoch()}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if published, err := s.m.History().ValueAt(wit.Epoch()); err == nil {
+		resp.Valid = published.Equals(wit.Accumulator())
+		resp.Current = wit.Epoch() == s.m.History().LatestEpoch()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeManagerError maps the errors of the manager to status codes.
+func writeManagerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, accumulator.ErrDuplicateMember):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, accumulator.ErrUnknownMember):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, accumulator.ErrUnknownEpoch):
+		writeError(w, http.StatusGone, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
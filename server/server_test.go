@@ -0,0 +1,306 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neucc1997/Accumulator"
+)
+
+const testToken = "secret"
+
+func newTestServer(t *testing.T, opts ...Option) (*Server, *httptest.Server) {
+	t.Helper()
+	mk, err := accumulator.SetupManagerKeyNamed("a-80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(accumulator.NewManager(mk), opts...)
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+// do sends a request with the test token and returns the status and body.
+func do(t *testing.T, ts *httptest.Server, method, path string, body []byte) (int, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, data
+}
+
+func enroll(t *testing.T, ts *httptest.Server, publicKey string) (int, []byte) {
+	t.Helper()
+	body, err := json.Marshal(accumulator.AccumulatorContent{PublicKey: publicKey, Role: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return do(t, ts, http.MethodPost, "/members", body)
+}
+
+func TestEndpoints(t *testing.T) {
+	s, ts := newTestServer(t, WithAuthorizer(BearerToken(testToken)))
+	pp := s.m.Params()
+
+	status, body := enroll(t, ts, "alice")
+	if status != http.StatusCreated {
+		t.Fatalf("enroll: got %d %s", status, body)
+	}
+	rec, err := pp.DecodeUpdateRecord(body)
+	if err != nil || rec.Epoch != 1 || len(rec.Added) != 1 {
+		t.Fatalf("enroll record: %+v, %v", rec, err)
+	}
+	if status, body = enroll(t, ts, "alice"); status != http.StatusConflict {
+		t.Errorf("second enroll: got %d %s, want 409", status, body)
+	}
+
+	status, body = do(t, ts, http.MethodGet, "/members/alice/witness", nil)
+	if status != http.StatusOK {
+		t.Fatalf("witness: got %d %s", status, body)
+	}
+	wit, err := pp.DecodeWitness(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness := body
+	if status, body = do(t, ts, http.MethodGet, "/members/bob/witness", nil); status != http.StatusNotFound {
+		t.Errorf("witness of unknown member: got %d %s, want 404", status, body)
+	}
+
+	status, body = do(t, ts, http.MethodGet, "/accumulator", nil)
+	if status != http.StatusOK {
+		t.Fatalf("accumulator: got %d %s", status, body)
+	}
+	acc, err := pp.DecodeAccumulator(body)
+	if err != nil || !acc.Equals(wit.Accumulator()) {
+		t.Errorf("accumulator does not match the witness: %v", err)
+	}
+
+	var resp VerifyResponse
+	status, body = do(t, ts, http.MethodPost, "/verify", witness)
+	if err := json.Unmarshal(body, &resp); status != http.StatusOK || err != nil {
+		t.Fatalf("verify: got %d %s", status, body)
+	}
+	if resp != (VerifyResponse{Valid: true, Epoch: 1, Current: true}) {
+		t.Errorf("verify: got %+v", resp)
+	}
+
+	if status, body = do(t, ts, http.MethodDelete, "/members/alice", nil); status != http.StatusOK {
+		t.Fatalf("revoke: got %d %s", status, body)
+	}
+	if status, body = do(t, ts, http.MethodDelete, "/members/alice", nil); status != http.StatusNotFound {
+		t.Errorf("second revoke: got %d %s, want 404", status, body)
+	}
+	status, body = do(t, ts, http.MethodPost, "/verify", witness)
+	if err := json.Unmarshal(body, &resp); status != http.StatusOK || err != nil {
+		t.Fatalf("verify: got %d %s", status, body)
+	}
+	if resp != (VerifyResponse{Valid: true, Epoch: 1, Current: false}) {
+		t.Errorf("verify after revocation: got %+v", resp)
+	}
+
+	status, body = do(t, ts, http.MethodGet, "/records?since=0", nil)
+	var records []json.RawMessage
+	if err := json.Unmarshal(body, &records); status != http.StatusOK || err != nil || len(records) != 2 {
+		t.Fatalf("records: got %d %s", status, body)
+	}
+	if status, body = do(t, ts, http.MethodGet, "/records?since=9", nil); status != http.StatusGone {
+		t.Errorf("records of a future epoch: got %d %s, want 410", status, body)
+	}
+	if status, body = do(t, ts, http.MethodGet, "/records", nil); status != http.StatusBadRequest {
+		t.Errorf("records without since: got %d %s, want 400", status, body)
+	}
+	if status, body = do(t, ts, http.MethodGet, "/rotations", nil); status != http.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+		t.Errorf("rotations: got %d %s", status, body)
+	}
+	if status, body = do(t, ts, http.MethodPut, "/members", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("PUT /members: got %d %s, want 405", status, body)
+	}
+}
+
+func TestRotationGone(t *testing.T) {
+	s, ts := newTestServer(t, WithAuthorizer(BearerToken(testToken)))
+	if status, body := enroll(t, ts, "alice"); status != http.StatusCreated {
+		t.Fatalf("enroll: got %d %s", status, body)
+	}
+	if _, err := s.Rotate(s.m.Key().Next()); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/records?since=1", "/events?since=1"} {
+		if status, body := do(t, ts, http.MethodGet, path, nil); status != http.StatusGone {
+			t.Errorf("%s: got %d %s, want 410", path, status, body)
+		}
+	}
+	status, body := do(t, ts, http.MethodGet, "/rotations", nil)
+	var rotations []json.RawMessage
+	if err := json.Unmarshal(body, &rotations); status != http.StatusOK || err != nil || len(rotations) != 1 {
+		t.Errorf("rotations: got %d %s", status, body)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	requests := []struct{ method, path string }{
+		{http.MethodPost, "/members"},
+		{http.MethodDelete, "/members/alice"},
+		{http.MethodGet, "/members/alice/witness"},
+	}
+	send := func(ts *httptest.Server, method, path, auth string) int {
+		body := `{"publicKey":"alice"}`
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	_, open := newTestServer(t)
+	for _, r := range requests {
+		if status := send(open, r.method, r.path, "Bearer "+testToken); status != http.StatusForbidden {
+			t.Errorf("%s %s without authorizer: got %d, want 403", r.method, r.path, status)
+		}
+	}
+
+	_, ts := newTestServer(t, WithAuthorizer(BearerToken(testToken)))
+	for _, r := range requests {
+		for _, auth := range []string{"", "Bearer wrong", testToken} {
+			if status := send(ts, r.method, r.path, auth); status != http.StatusUnauthorized {
+				t.Errorf("%s %s with %q: got %d, want 401", r.method, r.path, auth, status)
+			}
+		}
+	}
+
+	var calls []Action
+	_, ts = newTestServer(t, WithAuthorizer(func(r *http.Request, action Action, publicKey string) error {
+		calls = append(calls, action)
+		if publicKey != "alice" {
+			return errors.New("not alice")
+		}
+		return nil
+	}))
+	if status := send(ts, http.MethodPost, "/members", ""); status != http.StatusCreated {
+		t.Errorf("enroll: got %d", status)
+	}
+	if status := send(ts, http.MethodGet, "/members/bob/witness", ""); status != http.StatusForbidden {
+		t.Errorf("witness of bob: got %d, want 403", status)
+	}
+	if status := send(ts, http.MethodGet, "/members/alice/witness", ""); status != http.StatusOK {
+		t.Errorf("witness of alice: got %d", status)
+	}
+	want := []Action{ActionEnroll, ActionWitness, ActionWitness}
+	if len(calls) != len(want) {
+		t.Fatalf("authorizer calls: got %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("authorizer calls: got %v, want %v", calls, want)
+		}
+	}
+	if status := send(ts, http.MethodGet, "/accumulator", ""); status != http.StatusOK {
+		t.Errorf("accumulator: got %d", status)
+	}
+}
+
+// sseEvent is an event read from /events.
+type sseEvent struct {
+	id, event, data string
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return ev
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestEventsResume(t *testing.T) {
+	s, ts := newTestServer(t, WithAuthorizer(BearerToken(testToken)))
+	for _, name := range []string{"alice", "bob", "carol"} {
+		if status, body := enroll(t, ts, name); status != http.StatusCreated {
+			t.Fatalf("enroll %s: got %d %s", name, status, body)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events?since=0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Last-Event-ID takes precedence over since.
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("events: got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	r := bufio.NewReader(resp.Body)
+	pp := s.m.Params()
+	for _, id := range []string{"2", "3"} {
+		ev := readEvent(t, r)
+		if ev.id != id || ev.event != "update" {
+			t.Fatalf("got event %q %q, want update %s", ev.event, ev.id, id)
+		}
+		if _, err := pp.DecodeUpdateRecord([]byte(ev.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if status, body := enroll(t, ts, "dave"); status != http.StatusCreated {
+		t.Fatalf("enroll dave: got %d %s", status, body)
+	}
+	if ev := readEvent(t, r); ev.id != "4" {
+		t.Fatalf("got event %q after enrolling, want 4", ev.id)
+	}
+
+	if _, err := s.Rotate(s.m.Key().Next()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("stream not ended by the rotation: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package accumulator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/Nik-U/pbc"
+)
+
+// memberSignatureDomain separates the Fiat-Shamir challenges of member
+// signatures from other uses of the hash.
+const memberSignatureDomain = "accumulator/member-signature/v1"
+
+// MemberSignature is a signature of knowledge on a message: it proves that
+// the signer holds a witness for some element of the accumulator, without
+// telling which one. It is the membership proof made non-interactive with
+// the Fiat-Shamir transform.
+type MemberSignature struct {
+	WBar *pbc.Element // randomized witness W^r
+	VBar *pbc.Element // Acc^r / WBar^e = WBar^key
+	C    *pbc.Element // challenge, hash of the commitment and the message
+	S1   *pbc.Element // rho1 + c * r
+	S2   *pbc.Element // rho2 + c * e
+}
+
+// SignAsMember signs msg as a member of the accumulator wit was computed
+// against. element is the element of the witness.
+func SignAsMember(msg []byte, wit *Witness, element *pbc.Element) *MemberSignature {
+	pairing := wit.value.Pairing()
+	signer := &Witness{value: wit.value, element: element, acc: wit.acc}
+
+	r := randNonZero(pairing)
+	rho1 := pairing.NewZr().Rand()
+	rho2 := pairing.NewZr().Rand()
+	com := commitMembership(signer, r, rho1, rho2, pairing)
+	c := memberChallenge(msg, wit.acc.value, com, pairing)
+	resp := respondMembership(signer, r, rho1, rho2, c, pairing)
+	return &MemberSignature{WBar: com.WBar, VBar: com.VBar, C: c, S1: resp.S1, S2: resp.S2}
+}
+
+// VerifyMemberSignature checks that sig was made on msg by a member of acc.
+func VerifyMemberSignature(msg []byte, sig *MemberSignature, acc *Accumulator, pp *PublicParams) bool {
+	pairing := pp.Pairing
+	if !checkRandomizedWitness(sig.WBar, sig.VBar, pp.H, pp.PK2, pairing) {
+		return false
+	}
+	// T = Acc^s1 / WBar^s2 / VBar^c
+	t := pairing.NewG1().Sub(pairing.NewG1().PowZn(acc.value, sig.S1), pairing.NewG1().PowZn(sig.WBar, sig.S2))
+	t.Sub(t, pairing.NewG1().PowZn(sig.VBar, sig.C))
+	com := &MembershipCommitment{WBar: sig.WBar, VBar: sig.VBar, T: t}
+	return memberChallenge(msg, acc.value, com, pairing).Equals(sig.C)
+}
+
+// memberChallenge hashes the accumulator value, the commitment and the
+// message into Zr.
+func memberChallenge(msg []byte, acc *pbc.Element, com *MembershipCommitment, pairing *pbc.Pairing) *pbc.Element {
+	return hashToZr(pairing, memberSignatureDomain, acc.Bytes(), com.WBar.Bytes(), com.VBar.Bytes(), com.T.Bytes(), msg)
+}
+
+// hashToZr hashes the length-prefixed parts under domain into Zr.
+func hashToZr(pairing *pbc.Pairing, domain string, parts ...[]byte) *pbc.Element {
+	h := sha256.New()
+	for _, part := range append([][]byte{[]byte(domain)}, parts...) {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(part)))
+		h.Write(n[:])
+		h.Write(part)
+	}
+	return pairing.NewZr().SetFromHash(h.Sum(nil))
+}
+
+// MarshalBinary encodes the signature as a version byte followed by the
+// length-prefixed WBar, VBar, C, S1 and S2.
+func (sig *MemberSignature) MarshalBinary() ([]byte, error) {
+	e := newEncoder()
+	for _, el := range []*pbc.Element{sig.WBar, sig.VBar, sig.C, sig.S1, sig.S2} {
+		e.bytes(el.Bytes())
+	}
+	return e.buf, nil
+}
+
+type memberSignatureJSON struct {
+	Version int    `json:"version"`
+	WBar    string `json:"wbar"`
+	VBar    string `json:"vbar"`
+	C       string `json:"c"`
+	S1      string `json:"s1"`
+	S2      string `json:"s2"`
+}
+
+// MarshalJSON encodes the signature with the elements in hex.
+func (sig *MemberSignature) MarshalJSON() ([]byte, error) {
+	return json.Marshal(memberSignatureJSON{
+		Version: encodingVersion,
+		WBar:    hex.EncodeToString(sig.WBar.Bytes()),
+		VBar:    hex.EncodeToString(sig.VBar.Bytes()),
+		C:       hex.EncodeToString(sig.C.Bytes()),
+		S1:      hex.EncodeToString(sig.S1.Bytes()),
+		S2:      hex.EncodeToString(sig.S2.Bytes()),
+	})
+}
+
+// DecodeMemberSignature decodes a signature in either encoding into the
+// pairing of pp.
+func (pp *PublicParams) DecodeMemberSignature(data []byte) (*MemberSignature, error) {
+	sig := &MemberSignature{
+		WBar: pp.Pairing.NewG1(),
+		VBar: pp.Pairing.NewG1(),
+		C:    pp.Pairing.NewZr(),
+		S1:   pp.Pairing.NewZr(),
+		S2:   pp.Pairing.NewZr(),
+	}
+	if isJSON(data) {
+		var v memberSignatureJSON
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		if v.Version != encodingVersion {
+			return nil, ErrUnsupportedVersion
+		}
+		for _, f := range []struct {
+			el *pbc.Element
+			s  string
+		}{{sig.WBar, v.WBar}, {sig.VBar, v.VBar}, {sig.C, v.C}, {sig.S1, v.S1}, {sig.S2, v.S2}} {
+			if err := decodeHexElement(f.el, f.s); err != nil {
+				return nil, err
+			}
+		}
+		return sig, nil
+	}
+	d := newDecoder(data)
+	for _, el := range []*pbc.Element{sig.WBar, sig.VBar, sig.C, sig.S1, sig.S2} {
+		d.element(el)
+	}
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
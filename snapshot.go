@@ -0,0 +1,280 @@
+package accumulator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Nik-U/pbc"
+)
+
+const (
+	snapshotPrefix = "snapshot-"
+	snapshotSuffix = ".snap"
+
+	// snapshotsKept is the number of snapshots kept in a manager directory.
+	// The log goes back to the oldest of them, so a damaged newest snapshot
+	// can still be recovered from.
+	snapshotsKept = 2
+)
+
+var (
+	ErrCorruptSnapshot = errors.New("accumulator: snapshot is corrupt")
+	ErrStateMismatch   = errors.New("accumulator: accumulator value does not match the member set")
+	ErrNotPersistent   = errors.New("accumulator: manager has no directory")
+)
+
+// Snapshot is the full state of a Manager at one epoch. It holds the
+// public parameters, which also name the manager key through pk1, but
+// never the key itself.
+//
+// A snapshot file is the version byte followed by the length-prefixed
+// binary public parameters, the epoch, the length-prefixed accumulator
+// value, the member count and per member the length-prefixed element,
+// public key, attributes and role, as in LogEntry. The SHA-256 hash of all
+// of this is appended.
+type Snapshot struct {
+	Params  *PublicParams
+	Epoch   uint64
+	Value   *pbc.Element
+	Members []*Member
+}
+
+// Snapshot returns the current state of the manager.
+func (m *Manager) Snapshot() *Snapshot {
+	return &Snapshot{
+		Params:  m.key.Params,
+		Epoch:   m.acc.epoch,
+		Value:   m.acc.Value(),
+		Members: m.Members(),
+	}
+}
+
+// MarshalBinary encodes the snapshot with its trailing hash.
+func (s *Snapshot) MarshalBinary() ([]byte, error) {
+	params, err := s.Params.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	e := newEncoder()
+	e.bytes(params)
+	e.uint64(s.Epoch)
+	e.bytes(s.Value.Bytes())
+	e.members(s.Members)
+	sum := sha256.Sum256(e.buf)
+	return append(e.buf, sum[:]...), nil
+}
+
+// ReadSnapshot reads the snapshot at path, which must have been taken with
+// the public parameters pp. Its elements are decoded into the pairing of
+// pp.
+func ReadSnapshot(path string, pp *PublicParams) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < sha256.Size {
+		return nil, ErrCorruptSnapshot
+	}
+	body, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if want := sha256.Sum256(body); !bytes.Equal(sum, want[:]) {
+		return nil, ErrCorruptSnapshot
+	}
+	params, err := pp.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	d := newDecoder(body)
+	if stored := d.bytes(); d.err == nil && !bytes.Equal(stored, params) {
+		return nil, ErrKeyMismatch
+	}
+	s := &Snapshot{Params: pp, Epoch: d.uint64(), Value: d.element(pp.Pairing.NewG1())}
+	s.Members = d.members(pp.Pairing)
+	if err := d.finish(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSnapshot, err)
+	}
+	return s, nil
+}
+
+// restoreManager returns a manager in the state of s without recomputing
+// the accumulator. Use Manager.Verify to check the stored value.
+func restoreManager(key *ManagerKey, s *Snapshot) (*Manager, error) {
+	acc := &Accumulator{value: s.Value, epoch: s.Epoch}
+	m := &Manager{
+		key:         key,
+		acc:         acc,
+		history:     NewHistory(acc),
+		byElement:   make(map[string]*Member),
+		byPublicKey: make(map[string]*Member),
+	}
+	for _, member := range s.Members {
+		id := string(member.Element.Bytes())
+		if _, ok := m.byElement[id]; ok {
+			return nil, fmt.Errorf("%w: duplicate member", ErrCorruptSnapshot)
+		}
+		if _, ok := m.byPublicKey[member.Content.PublicKey]; ok && member.Content.PublicKey != "" {
+			return nil, fmt.Errorf("%w: duplicate public key", ErrCorruptSnapshot)
+		}
+		m.index(member)
+	}
+	return m, nil
+}
+
+// Verify recomputes the accumulator from the member set with the manager
+// key and compares it with the current value. It takes one exponentiation
+// but a multiplication per member.
+func (m *Manager) Verify() error {
+	pairing := m.key.Params.Pairing
+	elements := make([]*pbc.Element, 0, len(m.byElement))
+	for _, member := range m.byElement {
+		elements = append(elements, member.Element)
+	}
+	want := pairing.NewG1().PowZn(m.key.Params.PK1, productWithKey(elements, m.key.secret, pairing))
+	if !want.Equals(m.acc.value) {
+		return ErrStateMismatch
+	}
+	return nil
+}
+
+// WriteSnapshot stores the current state in the directory of the manager
+// and drops snapshots and log entries that are no longer needed. The
+// snapshot is written to a temporary file and renamed into place, so a
+// crash leaves either the old or the new set of snapshots.
+func (m *Manager) WriteSnapshot() error {
+	if m.dir == "" {
+		return ErrNotPersistent
+	}
+	data, err := m.Snapshot().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(m.dir, snapshotName(m.acc.epoch)), data, 0600); err != nil {
+		return err
+	}
+	epochs, err := snapshotEpochs(m.dir)
+	if err != nil {
+		return err
+	}
+	if len(epochs) < snapshotsKept {
+		return nil
+	}
+	for _, epoch := range epochs[snapshotsKept:] {
+		if err := os.Remove(filepath.Join(m.dir, snapshotName(epoch))); err != nil {
+			return err
+		}
+	}
+	return m.compactLog(epochs[snapshotsKept-1])
+}
+
+// compactLog rewrites the log without the entries up to epoch. The old log
+// stays open until the new one has replaced it.
+func (m *Manager) compactLog(epoch uint64) error {
+	path := filepath.Join(m.dir, LogFileName)
+	entries, _, err := ReadLog(path, m.key.Params.Pairing)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	for _, entry := range entries {
+		if entry.Epoch <= epoch {
+			continue
+		}
+		frame, err := encodeFrame(entry)
+		if err != nil {
+			return err
+		}
+		data = append(data, frame...)
+	}
+	err = writeFileAtomic(path, data, 0600)
+	// A failed write may still have renamed the new log into place, so the
+	// manager reopens whichever log is at path and keeps the old one only if
+	// that fails.
+	log, openErr := OpenLog(path)
+	if openErr != nil {
+		if err == nil {
+			err = openErr
+		}
+		return err
+	}
+	m.log.Close()
+	m.log = log
+	return err
+}
+
+func snapshotName(epoch uint64) string {
+	return fmt.Sprintf("%s%020d%s", snapshotPrefix, epoch, snapshotSuffix)
+}
+
+// snapshotEpochs returns the epochs of the snapshots in dir, newest first.
+func snapshotEpochs(dir string) ([]uint64, error) {
+	names, err := filepath.Glob(filepath.Join(dir, snapshotPrefix+"*"+snapshotSuffix))
+	if err != nil {
+		return nil, err
+	}
+	var epochs []uint64
+	for _, name := range names {
+		s := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), snapshotPrefix), snapshotSuffix)
+		if epoch, err := strconv.ParseUint(s, 10, 64); err == nil {
+			epochs = append(epochs, epoch)
+		}
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] > epochs[j] })
+	return epochs, nil
+}
+
+// restoreNewestSnapshot returns a manager in the state of the newest
+// snapshot in dir that can be read, or a new manager if there is none.
+func restoreNewestSnapshot(dir string, key *ManagerKey) (*Manager, error) {
+	epochs, err := snapshotEpochs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, epoch := range epochs {
+		s, err := ReadSnapshot(filepath.Join(dir, snapshotName(epoch)), key.Params)
+		if errors.Is(err, ErrKeyMismatch) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+		if m, err := restoreManager(key, s); err == nil {
+			return m, nil
+		}
+	}
+	return NewManager(key), nil
+}
+
+// writeFileAtomic replaces the file at path with data through a synced
+// temporary file in the same directory.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return syncDir(dir)
+}
@@ -0,0 +1,50 @@
+package accumulator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSnapshotCompactsAndKeepsLogging(t *testing.T) {
+	dir := t.TempDir()
+	mk := setupTypeA(t)
+	m, err := Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(i int) {
+		t.Helper()
+		if _, err := m.Add(AccumulatorContent{PublicKey: fmt.Sprint("member ", i), Role: "user"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 6; i++ {
+		add(i)
+		if i%2 == 1 {
+			if err := m.WriteSnapshot(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	add(6)
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m2, err := Recover(dir, mk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m2.Close()
+	if m2.Count() != 7 || !m2.Accumulator().Equals(m.Accumulator()) {
+		t.Fatalf("recovered %d members at epoch %d, want 7 at epoch %d", m2.Count(), m2.Accumulator().Epoch(), m.Accumulator().Epoch())
+	}
+	if err := m2.Verify(); err != nil {
+		t.Error(err)
+	}
+	for _, member := range m2.Members() {
+		if member.Content.Role != "user" {
+			t.Errorf("member %q lost its role", member.Content.PublicKey)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package accumulator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Nik-U/pbc"
+)
+
+var (
+	ErrInvalidThreshold = errors.New("accumulator: invalid threshold or number of shares")
+	ErrTooFewPartials   = errors.New("accumulator: not enough partial results")
+	ErrInvalidPartial   = errors.New("accumulator: partial result does not match the key share")
+	ErrInvalidDeal      = errors.New("accumulator: inversion deals are inconsistent")
+	ErrThresholdFailed  = errors.New("accumulator: combined result does not verify against pk2")
+)
+
+// ThresholdKey is the public side of a manager key split into shares with
+// SplitManagerKey: any Threshold shares together can act as the manager,
+// fewer learn nothing about the key.
+//
+// An addition needs Threshold share holders, each raising the accumulator
+// to its share. Deletions and witnesses need the inverse 1/(e+key), which
+// the share holders compute without revealing the key: they share a random
+// rho, publish z = rho*(e+key) and V^rho, and the result is (V^rho)^(1/z).
+// The product of two sharings has twice the degree, so this takes
+// 2*Threshold-1 share holders. Every combined result is checked against pk2.
+type ThresholdKey struct {
+	Params    *PublicParams
+	Threshold int
+	Shares    map[int]*pbc.Element // public share h^share in G2 per index
+}
+
+// KeyShare is the secret share of one share holder.
+type KeyShare struct {
+	Key    *ThresholdKey
+	Index  int
+	secret *pbc.Element
+}
+
+// SplitManagerKey splits mk into n shares with Shamir secret sharing over
+// Zr, any threshold of which can add elements. n must be at least
+// 2*threshold-1 so that deletions and witnesses remain possible.
+func SplitManagerKey(mk *ManagerKey, threshold, n int) (*ThresholdKey, []*KeyShare, error) {
+	if threshold < 1 || n < 2*threshold-1 {
+		return nil, nil, ErrInvalidThreshold
+	}
+	pp := mk.Params
+	coeffs := randomPolynomial(mk.secret, threshold-1, pp.Pairing)
+	tk := &ThresholdKey{Params: pp, Threshold: threshold, Shares: make(map[int]*pbc.Element)}
+	shares := make([]*KeyShare, n)
+	for i := range shares {
+		secret := evalPolynomial(coeffs, i+1, pp.Pairing)
+		tk.Shares[i+1] = pp.Pairing.NewG2().PowZn(pp.H, secret)
+		shares[i] = &KeyShare{Key: tk, Index: i + 1, secret: secret}
+	}
+	return tk, shares, nil
+}
+
+// Validate checks that the public shares lie on one polynomial of degree
+// Threshold-1 with pk2 at 0: the first Threshold shares must interpolate
+// to pk2 and to every other share.
+func (tk *ThresholdKey) Validate() error {
+	if tk.Threshold < 1 || len(tk.Shares) < 2*tk.Threshold-1 {
+		return ErrInvalidThreshold
+	}
+	indices := make([]int, 0, len(tk.Shares))
+	for i := range tk.Shares {
+		if i < 1 {
+			return ErrInvalidThreshold
+		}
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	pairing := tk.Params.Pairing
+	base := indices[:tk.Threshold]
+	for _, x := range append([]int{0}, indices[tk.Threshold:]...) {
+		value := pairing.NewG2().Set1()
+		for _, i := range base {
+			value.Add(value, pairing.NewG2().PowZn(tk.Shares[i], lagrangeAt(base, i, x, pairing)))
+		}
+		if x == 0 && !value.Equals(tk.Params.PK2) {
+			return ErrKeyMismatch
+		}
+		if x != 0 && !value.Equals(tk.Shares[x]) {
+			return fmt.Errorf("%w: share %d", ErrKeyMismatch, x)
+		}
+	}
+	return nil
+}
+
+// randomPolynomial returns the coefficients of a random polynomial of the
+// given degree with constant term c.
+func randomPolynomial(c *pbc.Element, degree int, pairing *pbc.Pairing) []*pbc.Element {
+	coeffs := []*pbc.Element{pairing.NewZr().Set(c)}
+	for i := 0; i < degree; i++ {
+		coeffs = append(coeffs, pairing.NewZr().Rand())
+	}
+	return coeffs
+}
+
+func evalPolynomial(coeffs []*pbc.Element, x int, pairing *pbc.Pairing) *pbc.Element {
+	xz := pairing.NewZr().SetInt32(int32(x))
+	y := pairing.NewZr().Set0()
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y.Mul(y, xz)
+		y.Add(y, coeffs[i])
+	}
+	return y
+}
+
+// lagrange returns the coefficient of the value at index i when the
+// polynomial through the points at indices is evaluated at 0.
+func lagrange(indices []int, i int, pairing *pbc.Pairing) *pbc.Element {
+	return lagrangeAt(indices, i, 0, pairing)
+}
+
+// lagrangeAt is lagrange for the evaluation at x.
+func lagrangeAt(indices []int, i, x int, pairing *pbc.Pairing) *pbc.Element {
+	num := pairing.NewZr().Set1()
+	den := pairing.NewZr().Set1()
+	for _, m := range indices {
+		if m == i {
+			continue
+		}
+		num.Mul(num, pairing.NewZr().SetInt32(int32(m-x)))
+		den.Mul(den, pairing.NewZr().SetInt32(int32(m-i)))
+	}
+	return num.Div(num, den)
+}
+
+// sortedIndices returns the keys of partials in increasing order.
+func sortedIndices(partials map[int]*pbc.Element) []int {
+	indices := make([]int, 0, len(partials))
+	for i := range partials {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// AddPartial is the contribution of the share holder to adding an element
+// to acc: acc^share.
+func (ks *KeyShare) AddPartial(acc *Accumulator) *pbc.Element {
+	return ks.Key.Params.Pairing.NewG1().PowZn(acc.value, ks.secret)
+}
+
+// Add adds element to acc with the AddPartial results of at least
+// Threshold share holders, keyed by index. Each partial is checked against
+// the public share of its holder. Like UpdateWithKey, acc moves to the next
+// epoch and the update is returned.
+func (tk *ThresholdKey) Add(acc *Accumulator, element *pbc.Element, partials map[int]*pbc.Element) (*UpdateRecord, error) {
+	if len(partials) < tk.Threshold {
+		return nil, ErrTooFewPartials
+	}
+	pp := tk.Params
+	pairing := pp.Pairing
+	for i, partial := range partials {
+		share, ok := tk.Shares[i]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown share %d", ErrInvalidPartial, i)
+		}
+		// e(acc^share, h) = e(acc, h^share)
+		if !pairing.NewGT().Pair(partial, pp.H).Equals(pairing.NewGT().Pair(acc.value, share)) {
+			return nil, fmt.Errorf("%w: share %d", ErrInvalidPartial, i)
+		}
+	}
+	indices := sortedIndices(partials)[:tk.Threshold]
+	// acc^(e+key) = acc^e * prod (acc^share_i)^lambda_i
+	value := pairing.NewG1().PowZn(acc.value, element)
+	for _, i := range indices {
+		value.Add(value, pairing.NewG1().PowZn(partials[i], lagrange(indices, i, pairing)))
+	}
+	next := &Accumulator{value: value, epoch: acc.epoch + 1}
+	if !VerifyTransition(acc, next, element, OpAdd, pp.PK2, pp.H, pairing) {
+		return nil, ErrThresholdFailed
+	}
+	*acc = *next
+	rec := acc.record([]*pbc.Element{element}, nil)
+	rec.Steps = []*pbc.Element{rec.Value}
+	return rec, nil
+}
+
+// InversionDeal is a message of the first round of the inversion protocol:
+// the shares of the random rho and of zero that the share holder From
+// deals to the participant To. Anyone who sees the deals of one sender to
+// Threshold participants learns its rho, and with it the key share of a
+// participant from that participant's partial result, so each deal must
+// reach its recipient only, over a private channel.
+type InversionDeal struct {
+	From         int
+	To           int
+	Participants []int
+	Rho          *pbc.Element // share of rho of the recipient
+	Zero         *pbc.Element // share of zero of the recipient
+}
+
+// DealInversion starts an inversion among participants, which must include
+// the share holder and number at least 2*Threshold-1. It returns one deal
+// per participant, including one to the share holder itself. Every
+// participant deals once, and each deal is delivered to its recipient.
+func (ks *KeyShare) DealInversion(participants []int) ([]*InversionDeal, error) {
+	tk := ks.Key
+	pairing := tk.Params.Pairing
+	if len(participants) < 2*tk.Threshold-1 {
+		return nil, ErrTooFewPartials
+	}
+	set := append([]int(nil), participants...)
+	sort.Ints(set)
+	found := false
+	for k, i := range set {
+		if _, ok := tk.Shares[i]; !ok || (k > 0 && set[k-1] == i) {
+			return nil, ErrInvalidDeal
+		}
+		found = found || i == ks.Index
+	}
+	if !found {
+		return nil, ErrInvalidDeal
+	}
+	// rho has the degree of the key sharing; the zero sharing has the
+	// degree of the product and re-randomizes it.
+	rho := randomPolynomial(pairing.NewZr().Rand(), tk.Threshold-1, pairing)
+	zero := randomPolynomial(pairing.NewZr().Set0(), 2*tk.Threshold-2, pairing)
+	deals := make([]*InversionDeal, len(set))
+	for k, j := range set {
+		deals[k] = &InversionDeal{
+			From:         ks.Index,
+			To:           j,
+			Participants: set,
+			Rho:          evalPolynomial(rho, j, pairing),
+			Zero:         evalPolynomial(zero, j, pairing),
+		}
+	}
+	return deals, nil
+}
+
+// InversionPartial is the second message of a share holder in the
+// inversion protocol. Unlike the results of AddPartial, the partials
+// cannot be checked one by one against the public shares: a wrong Z or D
+// only shows when the combined result fails its check against pk2, and
+// does not tell which participant sent it.
+type InversionPartial struct {
+	Index int
+	Z     *pbc.Element // share of rho*(e+key), masked with the zero sharing
+	D     *pbc.Element // base^(share of rho)
+}
+
+// InversionPartial computes the contribution of the share holder to
+// base^(1/(element+key)) from the deals it received, one from every
+// participant. Deals addressed to another participant are rejected.
+func (ks *KeyShare) InversionPartial(base, element *pbc.Element, deals []*InversionDeal) (*InversionPartial, error) {
+	pairing := ks.Key.Params.Pairing
+	if len(deals) == 0 {
+		return nil, ErrInvalidDeal
+	}
+	set := deals[0].Participants
+	if len(deals) != len(set) {
+		return nil, ErrInvalidDeal
+	}
+	rho := pairing.NewZr().Set0()
+	zero := pairing.NewZr().Set0()
+	from := make(map[int]bool)
+	for _, deal := range deals {
+		if deal.To != ks.Index || !equalInts(deal.Participants, set) || from[deal.From] {
+			return nil, ErrInvalidDeal
+		}
+		if deal.Rho == nil || deal.Zero == nil {
+			return nil, ErrInvalidDeal
+		}
+		from[deal.From] = true
+		rho.Add(rho, deal.Rho)
+		zero.Add(zero, deal.Zero)
+	}
+	for _, i := range set {
+		if !from[i] {
+			return nil, ErrInvalidDeal
+		}
+	}
+	z := pairing.NewZr().Add(element, ks.secret)
+	z.Mul(z, rho)
+	z.Add(z, zero)
+	return &InversionPartial{Index: ks.Index, Z: z, D: pairing.NewG1().PowZn(base, rho)}, nil
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// invert combines the partials of at least 2*Threshold-1 participants into
+// base^(1/(e+key)). The result is not checked yet.
+func (tk *ThresholdKey) invert(base *pbc.Element, partials []*InversionPartial) (*pbc.Element, error) {
+	pairing := tk.Params.Pairing
+	if len(partials) < 2*tk.Threshold-1 {
+		return nil, ErrTooFewPartials
+	}
+	byIndex := make(map[int]*InversionPartial)
+	for _, p := range partials {
+		byIndex[p.Index] = p
+	}
+	if len(byIndex) != len(partials) {
+		return nil, ErrInvalidDeal
+	}
+	indices := make([]int, 0, len(byIndex))
+	for i := range byIndex {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	z := pairing.NewZr().Set0()
+	for _, i := range indices[:2*tk.Threshold-1] {
+		z.Add(z, pairing.NewZr().Mul(byIndex[i].Z, lagrange(indices[:2*tk.Threshold-1], i, pairing)))
+	}
+	if z.Is0() {
+		return nil, ErrThresholdFailed
+	}
+	baseRho := pairing.NewG1().Set1()
+	for _, i := range indices[:tk.Threshold] {
+		baseRho.Add(baseRho, pairing.NewG1().PowZn(byIndex[i].D, lagrange(indices[:tk.Threshold], i, pairing)))
+	}
+	return baseRho.PowZn(baseRho, z.Invert(z)), nil
+}
+
+// Delete removes element from acc with the InversionPartial results for
+// base acc.Value(). acc moves to the next epoch and the update is returned.
+func (tk *ThresholdKey) Delete(acc *Accumulator, element *pbc.Element, partials []*InversionPartial) (*UpdateRecord, error) {
+	pp := tk.Params
+	value, err := tk.invert(acc.value, partials)
+	if err != nil {
+		return nil, err
+	}
+	next := &Accumulator{value: value, epoch: acc.epoch + 1}
+	if !VerifyTransition(acc, next, element, OpDelete, pp.PK2, pp.H, pp.Pairing) {
+		return nil, ErrThresholdFailed
+	}
+	*acc = *next
+	rec := acc.record(nil, []*pbc.Element{element})
+	rec.Steps = []*pbc.Element{rec.Value}
+	return rec, nil
+}
+
+// Witness issues the witness of element in acc from the InversionPartial
+// results for base acc.Value().
+func (tk *ThresholdKey) Witness(acc *Accumulator, element *pbc.Element, partials []*InversionPartial) (*Witness, error) {
+	pp := tk.Params
+	value, err := tk.invert(acc.value, partials)
+	if err != nil {
+		return nil, err
+	}
+	wit := NewWitness(value, element, acc)
+	if !VerifyWitness(wit, acc, pp.H, pp.PK2, element, pp.Pairing) {
+		return nil, ErrThresholdFailed
+	}
+	return wit, nil
+}
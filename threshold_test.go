@@ -0,0 +1,174 @@
+package accumulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nik-U/pbc"
+)
+
+// dealAll runs the first round of the inversion among shares and returns
+// the deals received by each participant.
+func dealAll(t *testing.T, shares []*KeyShare) map[int][]*InversionDeal {
+	t.Helper()
+	participants := make([]int, len(shares))
+	for i, ks := range shares {
+		participants[i] = ks.Index
+	}
+	inbox := make(map[int][]*InversionDeal)
+	for _, ks := range shares {
+		deals, err := ks.DealInversion(participants)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, deal := range deals {
+			if deal.From != ks.Index {
+				t.Fatalf("deal of %d is from %d", ks.Index, deal.From)
+			}
+			inbox[deal.To] = append(inbox[deal.To], deal)
+		}
+	}
+	return inbox
+}
+
+func invertAll(t *testing.T, shares []*KeyShare, base, element *pbc.Element) []*InversionPartial {
+	t.Helper()
+	inbox := dealAll(t, shares)
+	partials := make([]*InversionPartial, len(shares))
+	for i, ks := range shares {
+		partial, err := ks.InversionPartial(base, element, inbox[ks.Index])
+		if err != nil {
+			t.Fatal(err)
+		}
+		partials[i] = partial
+	}
+	return partials
+}
+
+func addAll(shares []*KeyShare, acc *Accumulator) map[int]*pbc.Element {
+	partials := make(map[int]*pbc.Element)
+	for _, ks := range shares {
+		partials[ks.Index] = ks.AddPartial(acc)
+	}
+	return partials
+}
+
+func TestThresholdManager(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	tk, shares, err := SplitManagerKey(mk, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tk.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// Every share is checked, not only those that interpolate to pk2.
+	for i := range tk.Shares {
+		share := tk.Shares[i]
+		tk.Shares[i] = pairing.NewG2().Rand()
+		if err := tk.Validate(); !errors.Is(err, ErrKeyMismatch) {
+			t.Errorf("Validate with a bad share %d: got %v, want ErrKeyMismatch", i, err)
+		}
+		tk.Shares[i] = share
+	}
+
+	acc := pp.NewAccumulator()
+	var elements []*pbc.Element
+	for i := 0; i < 3; i++ {
+		e := pairing.NewZr().Rand()
+		if _, err := tk.Add(acc, e, addAll(shares[i%2:i%2+2], acc)); err != nil {
+			t.Fatal(err)
+		}
+		elements = append(elements, e)
+	}
+	direct := pp.NewAccumulator()
+	direct.UpdateWithKey(elements, nil, mk.secret, pairing)
+	if !acc.value.Equals(direct.value) {
+		t.Fatal("threshold additions differ from the manager key")
+	}
+
+	wit, err := tk.Witness(acc, elements[1], invertAll(t, shares, acc.value, elements[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyWitness(wit, acc, pp.H, pp.PK2, elements[1], pairing) {
+		t.Fatal("threshold witness does not verify")
+	}
+
+	rec, err := tk.Delete(acc, elements[0], invertAll(t, shares, acc.value, elements[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wit.ApplyUpdates([]*UpdateRecord{rec}); err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyWitness(wit, acc, pp.H, pp.PK2, elements[1], pairing) {
+		t.Error("witness does not verify after the threshold deletion")
+	}
+	if VerifyWitness(NewWitness(acc.value, elements[0], acc), acc, pp.H, pp.PK2, elements[0], pairing) {
+		t.Error("deleted element still has a witness")
+	}
+}
+
+func TestThresholdRejects(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	tk, shares, err := SplitManagerKey(mk, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := pp.NewAccumulator()
+	e := pairing.NewZr().Rand()
+
+	if _, err := tk.Add(acc.Clone(), e, addAll(shares[:1], acc)); !errors.Is(err, ErrTooFewPartials) {
+		t.Errorf("Add with one partial: got %v, want ErrTooFewPartials", err)
+	}
+	partials := addAll(shares[:2], acc)
+	partials[2] = pairing.NewG1().Rand()
+	if _, err := tk.Add(acc.Clone(), e, partials); !errors.Is(err, ErrInvalidPartial) {
+		t.Errorf("Add with a bad partial: got %v, want ErrInvalidPartial", err)
+	}
+	partials = addAll(shares[:2], acc)
+	partials[7] = partials[2]
+	if _, err := tk.Add(acc.Clone(), e, partials); !errors.Is(err, ErrInvalidPartial) {
+		t.Errorf("Add with an unknown share: got %v, want ErrInvalidPartial", err)
+	}
+	if _, err := tk.Add(acc, e, addAll(shares[:2], acc)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := shares[0].DealInversion([]int{1, 2}); !errors.Is(err, ErrTooFewPartials) {
+		t.Errorf("DealInversion among two: got %v, want ErrTooFewPartials", err)
+	}
+	if _, err := shares[0].DealInversion([]int{2, 3, 4}); !errors.Is(err, ErrInvalidDeal) {
+		t.Errorf("DealInversion without the dealer: got %v, want ErrInvalidDeal", err)
+	}
+	partialsInv := invertAll(t, shares, acc.value, e)
+	if _, err := tk.Witness(acc, e, partialsInv[:2]); !errors.Is(err, ErrTooFewPartials) {
+		t.Errorf("Witness with two partials: got %v, want ErrTooFewPartials", err)
+	}
+
+	inbox := dealAll(t, shares)
+	own := inbox[1]
+	cases := []struct {
+		name  string
+		deals []*InversionDeal
+	}{
+		{"duplicate", []*InversionDeal{own[0], own[1], own[1]}},
+		{"missing", own[:2]},
+		{"foreign", []*InversionDeal{own[0], own[1], inbox[2][2]}},
+		{"all foreign", inbox[2]},
+		{"none", nil},
+	}
+	for _, c := range cases {
+		if _, err := shares[0].InversionPartial(acc.value, e, c.deals); !errors.Is(err, ErrInvalidDeal) {
+			t.Errorf("InversionPartial with %s deals: got %v, want ErrInvalidDeal", c.name, err)
+		}
+	}
+	if _, err := shares[0].InversionPartial(acc.value, e, own); err != nil {
+		t.Errorf("InversionPartial with its own deals: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package accumulator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Nik-U/pbc"
+)
+
+var ErrForgedTransition = errors.New("accumulator: transition does not match the announced element")
+
+// Op is the kind of change applied to an accumulator.
+type Op int
+
+const (
+	OpAdd Op = iota + 1
+	OpDelete
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpAdd:
+		return "add"
+	case OpDelete:
+		return "delete"
+	default:
+		return fmt.Sprintf("Op(%d)", int(op))
+	}
+}
+
+// VerifyTransition checks without the manager key that newAcc results from
+// oldAcc by adding or deleting element:
+//
+//	add:    e(newAcc, h) = e(oldAcc, pk2 * h^e)
+//	delete: e(oldAcc, h) = e(newAcc, pk2 * h^e)
+func VerifyTransition(oldAcc, newAcc *Accumulator, element *pbc.Element, op Op, pk2, h *pbc.Element, pairing *pbc.Pairing) bool {
+	from, to := oldAcc.value, newAcc.value
+	switch op {
+	case OpAdd:
+	case OpDelete:
+		from, to = to, from
+	default:
+		return false
+	}
+	temp1 := pairing.NewGT().Pair(to, h)
+	temp2 := pairing.NewGT().Pair(from, pairing.NewG2().Add(pk2, pairing.NewG2().PowZn(h, element)))
+	return temp1.Equals(temp2)
+}
+
+// TransitionError reports the first update of a chain that does not verify.
+type TransitionError struct {
+	Epoch uint64 // epoch of the offending record
+	Step  int    // index of the offending change within the record, deletions first
+	Err   error
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("update to epoch %d, step %d: %v", e.Epoch, e.Step, e.Err)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyRecords replays records on top of start and checks every single
+// change with VerifyTransition, and the batch data of batch records with
+// verifyBatch. The records must follow start without gaps. Compact records
+// can only be checked if they change a single element. The returned error
+// is a *TransitionError locating the first bad step.
+func VerifyRecords(start *Accumulator, records []*UpdateRecord, h, pk2 *pbc.Element, pairing *pbc.Pairing) error {
+	cur := start.Clone()
+	for _, rec := range records {
+		if rec.Epoch != cur.epoch+1 {
+			return &TransitionError{Epoch: rec.Epoch, Err: ErrMissingUpdate}
+		}
+		if elements, op, ok := rec.batch(); ok {
+			if step, err := verifyBatch(cur, rec, elements, op, h, pk2, pairing); err != nil {
+				return &TransitionError{Epoch: rec.Epoch, Step: step, Err: err}
+			}
+			cur = &Accumulator{value: rec.Value, epoch: rec.Epoch}
+			continue
+		}
+		n := len(rec.Deleted) + len(rec.Added)
+		steps := rec.Steps
+		if len(steps) != n {
+			if n != 1 {
+				return &TransitionError{Epoch: rec.Epoch, Err: ErrCompactRecord}
+			}
+			steps = []*pbc.Element{rec.Value}
+		}
+		for i := 0; i < n; i++ {
+			var op Op
+			var e *pbc.Element
+			if i < len(rec.Deleted) {
+				op, e = OpDelete, rec.Deleted[i]
+			} else {
+				op, e = OpAdd, rec.Added[i-len(rec.Deleted)]
+			}
+			next := &Accumulator{value: steps[i], epoch: rec.Epoch}
+			if !VerifyTransition(cur, next, e, op, pk2, h, pairing) {
+				return &TransitionError{Epoch: rec.Epoch, Step: i, Err: ErrForgedTransition}
+			}
+			cur = next
+		}
+		if !cur.value.Equals(rec.Value) {
+			return &TransitionError{Epoch: rec.Epoch, Step: n, Err: ErrInvalidRecord}
+		}
+		cur.epoch = rec.Epoch
+	}
+	return nil
+}
+
+// verifyBatch checks the batch record rec on top of cur. The batch data
+// must be successive powers of their base by the key,
+//
+//	e(B_(j+1), h) = e(B_j, pk2),
+//
+// and the values V before and V' after the update must satisfy V' = B^P(key)
+// for an addition, V = B^P(key) for a deletion, with P(x) the product of
+// (x + e_i) of degree n and leading coefficient 1:
+//
+//	e(V', h) = e(prod_(j<n) B_j^p_j, h) * e(B_(n-1), pk2)
+//
+// The returned index is that of the first bad power, or n if the values do
+// not match.
+func verifyBatch(cur *Accumulator, rec *UpdateRecord, elements []*pbc.Element, op Op, h, pk2 *pbc.Element, pairing *pbc.Pairing) (int, error) {
+	powers := rec.Powers
+	from, to := cur.value, rec.Value
+	if op == OpDelete {
+		from, to = to, from
+	}
+	if !powers[0].Equals(from) {
+		return 0, ErrForgedTransition
+	}
+	for j := 1; j < len(powers); j++ {
+		if !pairing.NewGT().Pair(powers[j], h).Equals(pairing.NewGT().Pair(powers[j-1], pk2)) {
+			return j, ErrForgedTransition
+		}
+	}
+	n := len(powers)
+	p := batchPolynomial(elements, pairing)
+	rhs := pairing.NewGT().Pair(powerProduct(powers, p[:n], pairing), h)
+	rhs.Mul(rhs, pairing.NewGT().Pair(powers[n-1], pk2))
+	if !pairing.NewGT().Pair(to, h).Equals(rhs) {
+		return n, ErrForgedTransition
+	}
+	return 0, nil
+}
@@ -0,0 +1,51 @@
+package accumulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nik-U/pbc"
+)
+
+func TestVerifyRecords(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	acc := pp.NewAccumulator()
+	elements := randElements(pairing, 3)
+	first := acc.UpdateWithKey(elements, nil, mk.secret, pairing)
+	start := acc.Clone()
+	// Deletions come first: steps 0 and 1 delete, step 2 adds.
+	rec := acc.UpdateWithKey(randElements(pairing, 1), elements[:2], mk.secret, pairing)
+	if err := VerifyRecords(pp.NewAccumulator(), []*UpdateRecord{first, rec}, pp.H, pp.PK2, pairing); err != nil {
+		t.Fatal(err)
+	}
+
+	for step := range rec.Steps {
+		forged := *rec
+		forged.Steps = append([]*pbc.Element(nil), rec.Steps...)
+		forged.Steps[step] = pairing.NewG1().Rand()
+		var terr *TransitionError
+		err := VerifyRecords(start, []*UpdateRecord{&forged}, pp.H, pp.PK2, pairing)
+		if !errors.As(err, &terr) || terr.Epoch != rec.Epoch || terr.Step != step || !errors.Is(err, ErrForgedTransition) {
+			t.Errorf("forged step %d: got %v", step, err)
+		}
+	}
+
+	forged := *rec
+	forged.Value = pairing.NewG1().Rand()
+	var terr *TransitionError
+	err := VerifyRecords(start, []*UpdateRecord{&forged}, pp.H, pp.PK2, pairing)
+	if !errors.As(err, &terr) || terr.Step != len(rec.Steps) || !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("value that does not follow the steps: got %v, want ErrInvalidRecord after the last step", err)
+	}
+
+	if err := VerifyRecords(pp.NewAccumulator(), []*UpdateRecord{rec}, pp.H, pp.PK2, pairing); !errors.Is(err, ErrMissingUpdate) {
+		t.Errorf("record after a gap: got %v, want ErrMissingUpdate", err)
+	}
+	compact := *rec
+	compact.Steps = nil
+	if err := VerifyRecords(start, []*UpdateRecord{&compact}, pp.H, pp.PK2, pairing); !errors.Is(err, ErrCompactRecord) {
+		t.Errorf("compact record: got %v, want ErrCompactRecord", err)
+	}
+}
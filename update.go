@@ -0,0 +1,275 @@
+package accumulator
+
+import (
+	"errors"
+
+	"github.com/Nik-U/pbc"
+)
+
+var (
+	ErrWitnessRevoked = errors.New("accumulator: the element of the witness was deleted")
+	ErrMissingUpdate  = errors.New("accumulator: update records are not consecutive")
+	ErrCompactRecord  = errors.New("accumulator: update record has no intermediate values")
+	ErrInvalidRecord  = errors.New("accumulator: update record is inconsistent")
+)
+
+// UpdateRecord describes one update of an accumulator, from epoch Epoch-1 to
+// Epoch. Deleted elements are removed before Added elements are added.
+//
+// Witness holders need more than the final value to follow an update
+// without the manager key, and a record carries one of two kinds of
+// intermediate data. Steps holds the accumulator value after each single
+// deletion and addition, in that order. Powers holds the batch data of a
+// record that only adds or only deletes n elements: B^(key^j) for j < n,
+// where B is the value before the additions or after the deletions; see
+// AddElementsWithKey. A record with neither, which only gives the value
+// after several changes, is compact and can be applied by nobody but the
+// manager.
+type UpdateRecord struct {
+	Epoch   uint64         // epoch of the accumulator after the update
+	Added   []*pbc.Element // elements added by the update
+	Deleted []*pbc.Element // elements deleted by the update
+	Steps   []*pbc.Element // intermediate accumulator values, may be empty
+	Powers  []*pbc.Element // batch data B^(key^j), may be empty
+	Value   *pbc.Element   // accumulator value after the update
+}
+
+// batch returns the elements and the operation of a batch record, and
+// false if rec does not have the form of one.
+func (rec *UpdateRecord) batch() ([]*pbc.Element, Op, bool) {
+	switch {
+	case len(rec.Powers) == 0 || len(rec.Steps) != 0:
+		return nil, 0, false
+	case len(rec.Deleted) == 0 && len(rec.Added) == len(rec.Powers):
+		return rec.Added, OpAdd, true
+	case len(rec.Added) == 0 && len(rec.Deleted) == len(rec.Powers):
+		return rec.Deleted, OpDelete, true
+	default:
+		return nil, 0, false
+	}
+}
+
+// wellFormed reports whether the intermediate data of rec match its
+// elements: steps for every change, batch data, or neither.
+func (rec *UpdateRecord) wellFormed() bool {
+	if len(rec.Powers) != 0 {
+		_, _, ok := rec.batch()
+		return ok
+	}
+	return len(rec.Steps) == 0 || len(rec.Steps) == len(rec.Deleted)+len(rec.Added)
+}
+
+// UpdateWithKey deletes and then adds the given elements one at a time and
+// returns the update with all intermediate values, ready to be published to
+// witness holders. The accumulator moves to the next epoch even if both
+// lists are empty.
+func (acc *Accumulator) UpdateWithKey(added, deleted []*pbc.Element, key *pbc.Element, pairing *pbc.Pairing) *UpdateRecord {
+	epoch := acc.epoch
+	steps := make([]*pbc.Element, 0, len(deleted)+len(added))
+	for _, e := range deleted {
+		acc.DeleteElementWithKey(e, key, pairing)
+		steps = append(steps, acc.value)
+	}
+	for _, e := range added {
+		acc.AddElementWithKey(e, key, pairing)
+		steps = append(steps, acc.value)
+	}
+	acc.epoch = epoch + 1
+	rec := acc.record(added, deleted)
+	rec.Steps = steps
+	return rec
+}
+
+// ApplyUpdates brings the witness to the epoch of the last record, using
+// only public information. Records the witness has already seen are
+// skipped; the others must follow each other without gaps. On error the
+// witness is left unchanged.
+func (wt *Witness) ApplyUpdates(records []*UpdateRecord) error {
+	pairing := wt.value.Pairing()
+	next := &Witness{value: wt.value, element: wt.element, acc: wt.acc}
+	for _, rec := range records {
+		if rec.Epoch <= next.acc.epoch {
+			continue
+		}
+		if rec.Epoch != next.acc.epoch+1 {
+			return ErrMissingUpdate
+		}
+		for _, e := range rec.Deleted {
+			if e.Equals(next.element) {
+				return ErrWitnessRevoked
+			}
+		}
+		if elements, op, ok := rec.batch(); ok {
+			if err := next.applyBatch(rec, elements, op, pairing); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(rec.Steps) != len(rec.Deleted)+len(rec.Added) {
+			return ErrCompactRecord
+		}
+		for i, e := range rec.Deleted {
+			next.DeleteElementForWitness(e, next.element, &Accumulator{value: rec.Steps[i]}, pairing)
+			next.acc.value = rec.Steps[i]
+		}
+		for i, e := range rec.Added {
+			next.AddElementForWitness(e, next.element, pairing)
+			next.acc.value = rec.Steps[len(rec.Deleted)+i]
+		}
+		if !next.acc.value.Equals(rec.Value) {
+			return ErrInvalidRecord
+		}
+		next.acc.epoch = rec.Epoch
+	}
+	*wt = *next
+	return nil
+}
+
+// applyBatch applies the batch record rec. With P(x) the product of
+// (x + e_i), Q(x) = (P(x) - P(-y)) / (x + y) for the element y of the
+// witness and B the base of the batch data, P(key)/(y+key) =
+// Q(key) + P(-y)/(y+key) gives
+//
+//	add:    W' = B^Q(key) * W^P(-y)
+//	delete: W' = (W / B^Q(key))^(1/P(-y))
+//
+// where B^Q(key) is the product of the batch data raised to the
+// coefficients of Q.
+func (wt *Witness) applyBatch(rec *UpdateRecord, elements []*pbc.Element, op Op, pairing *pbc.Pairing) error {
+	base := rec.Value
+	if op == OpAdd {
+		base = wt.acc.value
+	}
+	if !rec.Powers[0].Equals(base) {
+		return ErrInvalidRecord
+	}
+	q, rem := divideBatch(batchPolynomial(elements, pairing), wt.element, pairing)
+	if rem.Is0() {
+		return ErrInvalidRecord
+	}
+	bq := powerProduct(rec.Powers, q, pairing)
+	if op == OpAdd {
+		wt.value = pairing.NewG1().Add(bq, pairing.NewG1().PowZn(wt.value, rem))
+	} else {
+		w := pairing.NewG1().Sub(wt.value, bq)
+		wt.value = w.PowZn(w, rem.Invert(rem))
+	}
+	wt.acc = Accumulator{value: rec.Value, epoch: rec.Epoch}
+	return nil
+}
+
+// AddElementsWithKey adds all elements in a single update and returns its
+// record. The new value is a single exponentiation of the old value V by
+// the product of (e_i + key). Witness holders cannot follow such a product
+// without the key, so instead of the value after each addition the record
+// carries the batch data V^(key^j), j < n, for n elements. Computing them
+// takes n-1 exponentiations by the key, so the update as a whole costs
+// about as much as UpdateWithKey; only the new value is available after a
+// single exponentiation. Nothing happens and nil is returned if elements is
+// empty.
+func (acc *Accumulator) AddElementsWithKey(elements []*pbc.Element, key *pbc.Element, pairing *pbc.Pairing) *UpdateRecord {
+	if len(elements) == 0 {
+		return nil
+	}
+	powers := keyPowers(acc.value, len(elements), key, pairing)
+	acc.value = pairing.NewG1().PowZn(acc.value, productWithKey(elements, key, pairing))
+	acc.epoch++
+	rec := acc.record(elements, nil)
+	rec.Powers = powers
+	return rec
+}
+
+// DeleteElementsWithKey deletes all elements in a single update, with a
+// single exponentiation by the inverse of the product of (e_i + key). The
+// batch data are the powers V'^(key^j) of the new value V'. Like
+// AddElementsWithKey it is a no-op returning nil for an empty list.
+func (acc *Accumulator) DeleteElementsWithKey(elements []*pbc.Element, key *pbc.Element, pairing *pbc.Pairing) *UpdateRecord {
+	if len(elements) == 0 {
+		return nil
+	}
+	product := productWithKey(elements, key, pairing)
+	acc.value = pairing.NewG1().PowZn(acc.value, product.Invert(product))
+	acc.epoch++
+	rec := acc.record(nil, elements)
+	rec.Powers = keyPowers(acc.value, len(elements), key, pairing)
+	return rec
+}
+
+// keyPowers returns base^(key^j) for j < n.
+func keyPowers(base *pbc.Element, n int, key *pbc.Element, pairing *pbc.Pairing) []*pbc.Element {
+	powers := []*pbc.Element{pairing.NewG1().Set(base)}
+	for j := 1; j < n; j++ {
+		powers = append(powers, pairing.NewG1().PowZn(powers[j-1], key))
+	}
+	return powers
+}
+
+// batchPolynomial returns the coefficients of the product of (x + e) over
+// elements, lowest degree first.
+func batchPolynomial(elements []*pbc.Element, pairing *pbc.Pairing) []*pbc.Element {
+	p := []*pbc.Element{pairing.NewZr().Set1()}
+	for _, e := range elements {
+		next := make([]*pbc.Element, len(p)+1)
+		next[len(p)] = pairing.NewZr().Set0()
+		for i := range p {
+			next[i] = pairing.NewZr().Mul(p[i], e)
+		}
+		for i := range p {
+			next[i+1].Add(next[i+1], p[i])
+		}
+		p = next
+	}
+	return p
+}
+
+// divideBatch divides p by (x + y) and returns the quotient and the
+// remainder p(-y).
+func divideBatch(p []*pbc.Element, y *pbc.Element, pairing *pbc.Pairing) ([]*pbc.Element, *pbc.Element) {
+	n := len(p) - 1
+	q := make([]*pbc.Element, n)
+	carry := pairing.NewZr().Set(p[n])
+	for i := n - 1; i >= 0; i-- {
+		q[i] = carry
+		carry = pairing.NewZr().Sub(p[i], pairing.NewZr().Mul(y, carry))
+	}
+	return q, carry
+}
+
+// powerProduct returns the product of powers[j]^coeffs[j].
+func powerProduct(powers, coeffs []*pbc.Element, pairing *pbc.Pairing) *pbc.Element {
+	product := pairing.NewG1().Set1()
+	for j, c := range coeffs {
+		product.Add(product, pairing.NewG1().PowZn(powers[j], c))
+	}
+	return product
+}
+
+// productWithKey returns the product of (e + key) over elements.
+func productWithKey(elements []*pbc.Element, key *pbc.Element, pairing *pbc.Pairing) *pbc.Element {
+	product := pairing.NewZr().Set1()
+	for _, e := range elements {
+		product.Mul(product, pairing.NewZr().Add(e, key))
+	}
+	return product
+}
+
+// record returns the update that led to the current state of acc.
+func (acc *Accumulator) record(added, deleted []*pbc.Element) *UpdateRecord {
+	return &UpdateRecord{
+		Epoch:   acc.epoch,
+		Added:   copyElements(added),
+		Deleted: copyElements(deleted),
+		Value:   acc.value.Pairing().NewG1().Set(acc.value),
+	}
+}
+
+func copyElements(elements []*pbc.Element) []*pbc.Element {
+	if len(elements) == 0 {
+		return nil
+	}
+	copied := make([]*pbc.Element, len(elements))
+	for i, e := range elements {
+		copied[i] = e.Pairing().NewZr().Set(e)
+	}
+	return copied
+}
@@ -0,0 +1,166 @@
+package accumulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nik-U/pbc"
+)
+
+func randElements(pairing *pbc.Pairing, n int) []*pbc.Element {
+	elements := make([]*pbc.Element, n)
+	for i := range elements {
+		elements[i] = pairing.NewZr().Rand()
+	}
+	return elements
+}
+
+func TestBatchUpdates(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	acc := pp.NewAccumulator()
+	start := acc.Clone()
+	own := pairing.NewZr().Rand()
+	outsider := pairing.NewZr().Rand()
+	first := acc.UpdateWithKey([]*pbc.Element{own}, nil, mk.secret, pairing)
+	wit := acc.EasyWayToGetWitness(own, mk.secret, pairing)
+	nm := acc.NonMembershipWitnessWithKey(outsider, mk.secret, pp.G, pairing)
+
+	added := randElements(pairing, 4)
+	add := acc.AddElementsWithKey(added, mk.secret, pairing)
+	if len(add.Steps) != 0 || len(add.Powers) != len(added) {
+		t.Fatalf("batch addition has %d steps and %d powers", len(add.Steps), len(add.Powers))
+	}
+	direct := start.Clone()
+	direct.UpdateWithKey(append([]*pbc.Element{own}, added...), nil, mk.secret, pairing)
+	if !acc.value.Equals(direct.value) {
+		t.Fatal("batch addition differs from single additions")
+	}
+	del := acc.DeleteElementsWithKey(added[1:], mk.secret, pairing)
+	records := []*UpdateRecord{first, add, del}
+	if err := VerifyRecords(start, records, pp.H, pp.PK2, pairing); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := wit.ApplyUpdates(records[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyWitness(wit, acc, pp.H, pp.PK2, own, pairing) {
+		t.Error("witness does not verify after the batches")
+	}
+	if err := nm.ApplyUpdates(records[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyNonMembership(nm, acc, pp.G, pp.H, pp.PK2, outsider, pairing) {
+		t.Error("non-membership witness does not verify after the batches")
+	}
+
+	// Records survive both encodings with their batch data.
+	for _, rec := range records[1:] {
+		bin, err := rec.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		js, err := rec.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, data := range [][]byte{bin, js} {
+			decoded, err := pp.DecodeUpdateRecord(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(decoded.Powers) != len(rec.Powers) || !decoded.Powers[1].Equals(rec.Powers[1]) {
+				t.Error("decoded record lost its batch data")
+			}
+		}
+	}
+}
+
+func TestBatchRecordsChecked(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	acc := pp.NewAccumulator()
+	acc.UpdateWithKey(randElements(pairing, 1), nil, mk.secret, pairing)
+	start := acc.Clone()
+	rec := acc.AddElementsWithKey(randElements(pairing, 3), mk.secret, pairing)
+
+	forged := *rec
+	forged.Powers = append([]*pbc.Element(nil), rec.Powers...)
+	forged.Powers[2] = pairing.NewG1().Rand()
+	var terr *TransitionError
+	if err := VerifyRecords(start, []*UpdateRecord{&forged}, pp.H, pp.PK2, pairing); !errors.As(err, &terr) || terr.Step != 2 {
+		t.Errorf("forged batch data: got %v, want a TransitionError at step 2", err)
+	}
+	forged.Powers = rec.Powers
+	forged.Value = pairing.NewG1().Rand()
+	if err := VerifyRecords(start, []*UpdateRecord{&forged}, pp.H, pp.PK2, pairing); !errors.As(err, &terr) || terr.Step != 3 {
+		t.Errorf("forged batch value: got %v, want a TransitionError at step 3", err)
+	}
+
+	forged = *rec
+	forged.Powers = rec.Powers[:2]
+	data, err := forged.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pp.DecodeUpdateRecord(data); !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("batch data of the wrong length: got %v, want ErrInvalidRecord", err)
+	}
+}
+
+func TestApplyUpdates(t *testing.T) {
+	mk := setupTypeA(t)
+	pp := mk.Params
+	pairing := pp.Pairing
+	acc := pp.NewAccumulator()
+	own := pairing.NewZr().Rand()
+	others := randElements(pairing, 3)
+	acc.UpdateWithKey([]*pbc.Element{own, others[0]}, nil, mk.secret, pairing)
+	wit := acc.EasyWayToGetWitness(own, mk.secret, pairing)
+	startEpoch, startValue := wit.Epoch(), pairing.NewG1().Set(wit.Value())
+	records := []*UpdateRecord{
+		acc.UpdateWithKey(others[1:2], nil, mk.secret, pairing),
+		acc.UpdateWithKey(others[2:], others[:1], mk.secret, pairing),
+		acc.UpdateWithKey(nil, others[1:2], mk.secret, pairing),
+	}
+	unchanged := func(what string) {
+		t.Helper()
+		if wit.Epoch() != startEpoch || !wit.Value().Equals(startValue) {
+			t.Errorf("%s changed the witness", what)
+		}
+	}
+
+	if err := wit.ApplyUpdates([]*UpdateRecord{records[0], records[2]}); !errors.Is(err, ErrMissingUpdate) {
+		t.Errorf("records with a gap: got %v, want ErrMissingUpdate", err)
+	}
+	unchanged("records with a gap")
+	compact := *records[1]
+	compact.Steps = nil
+	if err := wit.ApplyUpdates([]*UpdateRecord{records[0], &compact}); !errors.Is(err, ErrCompactRecord) {
+		t.Errorf("compact record: got %v, want ErrCompactRecord", err)
+	}
+	unchanged("a compact record")
+
+	// Records seen before are skipped, in one call or across calls.
+	if err := wit.ApplyUpdates([]*UpdateRecord{records[0], records[0], records[1]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wit.ApplyUpdates(records); err != nil {
+		t.Fatal(err)
+	}
+	if wit.Epoch() != acc.Epoch() || !VerifyWitness(wit, acc, pp.H, pp.PK2, own, pairing) {
+		t.Fatal("witness does not verify after the updates")
+	}
+
+	epoch, value := wit.Epoch(), pairing.NewG1().Set(wit.Value())
+	revoke := acc.UpdateWithKey(randElements(pairing, 1), []*pbc.Element{own}, mk.secret, pairing)
+	if err := wit.ApplyUpdates([]*UpdateRecord{revoke}); !errors.Is(err, ErrWitnessRevoked) {
+		t.Errorf("revocation of its own element: got %v, want ErrWitnessRevoked", err)
+	}
+	if wit.Epoch() != epoch || !wit.Value().Equals(value) {
+		t.Error("revocation changed the witness")
+	}
+}